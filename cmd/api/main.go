@@ -11,9 +11,12 @@ import (
 	"time"
 
 	"github.com/abdullinmm/user-management-api/internal/config"
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
 	httphandler "github.com/abdullinmm/user-management-api/internal/handler/http" // ← Правильный импорт
 	"github.com/abdullinmm/user-management-api/internal/middleware"
 	jwtpkg "github.com/abdullinmm/user-management-api/internal/pkg/jwt"
+	"github.com/abdullinmm/user-management-api/internal/pkg/pubsub"
+	"github.com/abdullinmm/user-management-api/internal/repository/cache"
 	"github.com/abdullinmm/user-management-api/internal/repository/postgresql"
 	"github.com/abdullinmm/user-management-api/internal/usecase"
 
@@ -38,23 +41,62 @@ func main() {
 
 	log.Println("Successfully connected to database")
 
+	// Background workers stop when the server shuts down
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
 	// Initialize repositories
 	userRepo := postgresql.NewUserRepository(dbPool)
 	taskRepo := postgresql.NewTaskRepository(dbPool)
-	balanceRepo := postgresql.NewBalanceRepository(dbPool)
+	leaderboardCache := cache.NewLeaderboardCache(postgresql.NewBalanceRepository(dbPool))
+	balanceRepo := leaderboardCache
 	transactionRepo := postgresql.NewTransactionRepository(dbPool)
-	userTaskRepo := postgresql.NewUserTaskRepository(dbPool)
+	userTaskRepo := postgresql.NewUserTaskRepository(dbPool, cfg.Timezone)
+	currencyRepo := postgresql.NewCurrencyRepository(dbPool)
+	levelRepo := postgresql.NewLevelRepository(dbPool)
+	badgeRepo := postgresql.NewBadgeRepository(dbPool)
+	streakRepo := postgresql.NewStreakRepository(dbPool)
+	questRepo := postgresql.NewQuestChainRepository(dbPool)
+	partnerRepo := postgresql.NewPartnerRepository(dbPool)
+	segmentRepo := postgresql.NewSegmentRepository(dbPool)
+	campaignRepo := postgresql.NewCampaignRepository(dbPool)
+	exportRepo := postgresql.NewDataExportRepository(dbPool)
+	snapshotReader := postgresql.NewSnapshotReader(dbPool)
+
+	// Initialize live update broker
+	eventBroker := pubsub.NewBroker[entities.Event]()
 
 	// Initialize use cases
-	userUseCase := usecase.NewUserUseCase(userRepo, balanceRepo, transactionRepo, cfg.ReferralBonus, cfg.RefereeBonus)
-	taskUseCase := usecase.NewTaskUseCase(taskRepo, userTaskRepo, balanceRepo, transactionRepo)
-	balanceUseCase := usecase.NewBalanceUseCase(balanceRepo, transactionRepo)
+	badgeUseCase := usecase.NewBadgeUseCase(badgeRepo, userRepo, userTaskRepo, balanceRepo)
+	campaignUseCase := usecase.NewCampaignUseCase(campaignRepo)
+	userUseCase := usecase.NewUserUseCase(userRepo, balanceRepo, transactionRepo, currencyRepo, badgeUseCase, campaignUseCase, eventBroker, leaderboardCache, cfg.ReferralBonus, cfg.RefereeBonus, cfg.Levels, cfg.UsernamePolicy)
+	streakUseCase := usecase.NewStreakUseCase(streakRepo, balanceRepo, leaderboardCache, eventBroker, cfg.StreakRules, cfg.StreakFreezePrice)
+	taskUseCase := usecase.NewTaskUseCase(taskRepo, userTaskRepo, questRepo, segmentRepo, balanceRepo, transactionRepo, levelRepo, badgeUseCase, streakUseCase, campaignUseCase, eventBroker, cfg.Levels, cfg.DefaultLocale)
+	partnerUseCase := usecase.NewPartnerUseCase(partnerRepo, taskRepo, userRepo, taskUseCase, cfg.CallbackMaxSkew)
+	segmentUseCase := usecase.NewSegmentUseCase(segmentRepo)
+	erasureUseCase := usecase.NewErasureUseCase(userRepo, balanceRepo, leaderboardCache, cfg.DeletionGrace)
+	exportUseCase := usecase.NewExportUseCase(snapshotReader, exportRepo, userRepo, balanceRepo, transactionRepo, userTaskRepo, cfg.DataExportTTL)
+	importUseCase := usecase.NewImportUseCase(userRepo, currencyRepo, leaderboardCache, cfg.UsernamePolicy, cfg.ImportBatchSize)
+	eventUseCase := usecase.NewEventUseCase(eventBroker)
+	balanceUseCase := usecase.NewBalanceUseCase(balanceRepo, transactionRepo, currencyRepo, cfg.Timezone)
+
+	// Keep in-memory leaderboards consistent with the database
+	go leaderboardCache.Run(bgCtx, cfg.LeaderboardResync)
+
+	// Purge partner callback nonces that can no longer be replayed
+	go partnerUseCase.Run(bgCtx)
+
+	// Anonymise deleted users once their grace period is over
+	go erasureUseCase.Run(bgCtx, cfg.ErasureInterval)
+
+	// Build queued personal data exports
+	go exportUseCase.Run(bgCtx)
 
 	// Initialize JWT manager
 	jwtManager := jwtpkg.NewManager(cfg.JWTSecret)
 
 	// Initialize HTTP router
-	router := setupRouter(userUseCase, taskUseCase, balanceUseCase, jwtManager)
+	router := setupRouter(userUseCase, taskUseCase, balanceUseCase, badgeUseCase, streakUseCase, partnerUseCase, segmentUseCase, campaignUseCase, erasureUseCase, exportUseCase, importUseCase, eventUseCase, jwtManager)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -79,6 +121,7 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopBackground()
 
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -125,55 +168,205 @@ func setupRouter(
 	userUC *usecase.UserUseCase,
 	taskUC *usecase.TaskUseCase,
 	balanceUC *usecase.BalanceUseCase,
+	badgeUC *usecase.BadgeUseCase,
+	streakUC *usecase.StreakUseCase,
+	partnerUC *usecase.PartnerUseCase,
+	segmentUC *usecase.SegmentUseCase,
+	campaignUC *usecase.CampaignUseCase,
+	erasureUC *usecase.ErasureUseCase,
+	exportUC *usecase.ExportUseCase,
+	importUC *usecase.ImportUseCase,
+	eventUC *usecase.EventUseCase,
 	jwtManager *jwtpkg.Manager,
 ) http.Handler {
 	r := chi.NewRouter()
 
 	// Import handlers
-	userHandler := httphandler.NewUserHandler(userUC)
+	userHandler := httphandler.NewUserHandler(userUC, streakUC)
 	taskHandler := httphandler.NewTaskHandler(taskUC)
 	balanceHandler := httphandler.NewBalanceHandler(balanceUC)
+	badgeHandler := httphandler.NewBadgeHandler(badgeUC)
+	streakHandler := httphandler.NewStreakHandler(streakUC)
+	moderationHandler := httphandler.NewModerationHandler(taskUC, userUC)
+	partnerHandler := httphandler.NewPartnerHandler(partnerUC)
+	segmentHandler := httphandler.NewSegmentHandler(segmentUC)
+	campaignHandler := httphandler.NewCampaignHandler(campaignUC)
+	erasureHandler := httphandler.NewErasureHandler(erasureUC)
+	exportHandler := httphandler.NewExportHandler(exportUC)
+	importHandler := httphandler.NewImportHandler(importUC)
+	eventHandler := httphandler.NewEventHandler(eventUC)
 
 	// Global middleware
 	r.Use(middleware2.RequestID)
 	r.Use(middleware2.RealIP)
 	r.Use(middleware2.Logger)
 	r.Use(middleware2.Recoverer)
-	r.Use(middleware2.Timeout(60 * time.Second))
 
-	// Health check (no auth required)
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`{"status":"ok"}`))
-	})
+	// Request timeout is applied per group so that event streams are not cut off
+	timeout := middleware2.Timeout(60 * time.Second)
 
-	// Public routes (no auth required)
-	r.Route("/api/v1/auth", func(r chi.Router) {
-		r.Post("/register", userHandler.Register)
-	})
+	r.Group(func(r chi.Router) {
+		r.Use(timeout)
+
+		// Health check (no auth required)
+		r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"status":"ok"}`))
+		})
+
+		// Public routes (no auth required)
+		r.Route("/api/v1/auth", func(r chi.Router) {
+			r.Post("/register", userHandler.Register)
+		})
+
+		// Public tasks endpoint (no auth)
+		r.Route("/api/v1/tasks", func(r chi.Router) {
+			r.Get("/", taskHandler.ListActive)
+		})
 
-	// Public tasks endpoint (no auth)
-	r.Route("/api/v1/tasks", func(r chi.Router) {
-		r.Get("/", taskHandler.ListActive)
+		// Public currencies endpoint (no auth)
+		r.Get("/api/v1/currencies", balanceHandler.Currencies)
+
+		// Public running campaigns endpoint (no auth)
+		r.Get("/api/v1/campaigns", campaignHandler.ListRunning)
+
+		// Partner callbacks (authenticated by HMAC signature)
+		r.Post("/api/v1/partners/{code}/callbacks", partnerHandler.Callback)
 	})
 
 	// Protected routes (JWT auth required)
 	r.Route("/api/v1/users", func(r chi.Router) {
 		// Apply JWT middleware to all routes in this group
-		r.Use(middleware.Auth(jwtManager))
+		r.Use(middleware.Auth(jwtManager, userUC.CheckAccess))
+
+		// GET /users/{id}/events - live updates stream (SSE, no request timeout)
+		r.Get("/{id}/events", eventHandler.Stream)
+
+		r.Group(func(r chi.Router) {
+			r.Use(timeout)
+
+			// GET /users/{id} - get user profile
+			r.Get("/{id}", userHandler.GetProfile)
+
+			// PATCH /users/{id} - update username and profile (JSON merge patch)
+			r.Patch("/{id}", userHandler.UpdateProfile)
+
+			// DELETE /users/{id} - delete own account (anonymised after grace period)
+			r.Delete("/{id}", erasureHandler.DeleteAccount)
+
+			// POST /users/{id}/exports - request personal data export (json or zip)
+			r.Post("/{id}/exports", exportHandler.Request)
+
+			// GET /users/{id}/exports - list personal data exports
+			r.Get("/{id}/exports", exportHandler.List)
+
+			// GET /users/{id}/exports/{exportID} - get data export status
+			r.Get("/{id}/exports/{exportID}", exportHandler.Get)
+
+			// GET /users/{id}/exports/{exportID}/download - download data export archive
+			r.Get("/{id}/exports/{exportID}/download", exportHandler.Download)
+
+			// GET /users/{id}/username-history - get previous usernames
+			r.Get("/{id}/username-history", userHandler.GetUsernameHistory)
+
+			// GET /users/{id}/status - get user status
+			r.Get("/{id}/status", userHandler.GetStatus)
+
+			// GET /users/leaderboard - get top users
+			r.Get("/leaderboard", balanceHandler.Leaderboard)
+
+			// GET /users/{id}/rank - get user rank and neighbours
+			r.Get("/{id}/rank", balanceHandler.Rank)
+
+			// GET /users/{id}/tasks - get available and completed tasks
+			r.Get("/{id}/tasks", taskHandler.ListUserTasks)
+
+			// GET /users/{id}/quests - get quest chains progress
+			r.Get("/{id}/quests", taskHandler.ListUserQuests)
+
+			// POST /users/{id}/task/complete - complete task
+			r.Post("/{id}/task/complete", taskHandler.CompleteTask)
+
+			// POST /users/{id}/referrer - set referrer
+			r.Post("/{id}/referrer", userHandler.SetReferrer)
+
+			// GET /users/{id}/badges - list earned badges
+			r.Get("/{id}/badges", badgeHandler.ListUserBadges)
+
+			// GET /users/{id}/streaks - get streaks and streak freezes
+			r.Get("/{id}/streaks", streakHandler.GetStreaks)
+
+			// POST /users/{id}/streak-freezes - buy a streak freeze
+			r.Post("/{id}/streak-freezes", streakHandler.PurchaseFreeze)
+		})
+	})
+
+	// Moderation routes (JWT auth and moderator role required)
+	r.Route("/api/v1/moderation", func(r chi.Router) {
+		r.Use(timeout)
+		r.Use(middleware.Auth(jwtManager, userUC.CheckAccess))
+		r.Use(middleware.RequireRole(userUC.GetRole, entities.RoleModerator, entities.RoleAdmin))
+
+		// GET /moderation/submissions - list submissions awaiting verification
+		r.Get("/submissions", moderationHandler.ListPending)
+
+		// POST /moderation/submissions/{id}/approve - approve submission and award points
+		r.Post("/submissions/{id}/approve", moderationHandler.Approve)
+
+		// POST /moderation/submissions/{id}/reject - reject submission
+		r.Post("/submissions/{id}/reject", moderationHandler.Reject)
+
+		// PUT /moderation/users/{id}/status - suspend, ban or reactivate user
+		r.Put("/users/{id}/status", moderationHandler.SetUserStatus)
+
+		// GET /moderation/users/{id}/status-changes - user status audit
+		r.Get("/users/{id}/status-changes", moderationHandler.ListUserStatusChanges)
+	})
+
+	r.Route("/api/v1/admin", func(r chi.Router) {
+		r.Use(middleware.Auth(jwtManager, userUC.CheckAccess))
+		r.Use(middleware.RequireRole(userUC.GetRole, entities.RoleAdmin))
+
+		// POST /admin/users/import - bulk import users from CSV or NDJSON (no request timeout)
+		r.Post("/users/import", importHandler.Import)
+
+		r.Group(func(r chi.Router) {
+			r.Use(timeout)
+
+			// GET /admin/users - search users by username, filters and cursor
+			r.Get("/users", userHandler.Search)
+
+			// GET /admin/segments - list user segments targeted by tasks
+			r.Get("/segments", segmentHandler.List)
+
+			// PUT /admin/segments/{code} - create or rename segment
+			r.Put("/segments/{code}", segmentHandler.Save)
+
+			// POST /admin/segments/{code}/members - add users to segment
+			r.Post("/segments/{code}/members", segmentHandler.AddMembers)
+
+			// DELETE /admin/segments/{code}/members/{userID} - remove user from segment
+			r.Delete("/segments/{code}/members/{userID}", segmentHandler.RemoveMember)
+
+			// GET /admin/tasks/{id}/versions - task title and reward history
+			r.Get("/tasks/{id}/versions", taskHandler.ListVersions)
+
+			// GET /admin/campaigns - list all reward campaigns
+			r.Get("/campaigns", campaignHandler.ListAll)
 
-		// GET /users/{id}/status - get user status
-		r.Get("/{id}/status", userHandler.GetStatus)
+			// POST /admin/campaigns - start reward campaign
+			r.Post("/campaigns", campaignHandler.Create)
 
-		// GET /users/leaderboard - get top users
-		r.Get("/leaderboard", balanceHandler.Leaderboard)
+			// POST /admin/users/{id}/restore - restore deleted user during grace period
+			r.Post("/users/{id}/restore", erasureHandler.Restore)
 
-		// POST /users/{id}/task/complete - complete task
-		r.Post("/{id}/task/complete", taskHandler.CompleteTask)
+			// POST /admin/users/{id}/erasure - anonymise user now and get deletion receipt
+			r.Post("/users/{id}/erasure", erasureHandler.Erase)
 
-		// POST /users/{id}/referrer - set referrer
-		r.Post("/{id}/referrer", userHandler.SetReferrer)
+			// GET /admin/users/{id}/erasure - get deletion receipt
+			r.Get("/users/{id}/erasure", erasureHandler.GetReceipt)
+		})
 	})
 
 	return r
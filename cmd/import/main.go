@@ -0,0 +1,152 @@
+// Command import creates users in bulk from a CSV or NDJSON file, e.g. when
+// migrating a partner's user base, and prints a JSON report of skipped rows.
+//
+// Usage:
+//
+//	go run ./cmd/import -file users.csv [-format csv|ndjson] [-currency points] [-batch 1000] [-report report.json]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/abdullinmm/user-management-api/internal/config"
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/repository/postgresql"
+	"github.com/abdullinmm/user-management-api/internal/usecase"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// noLeaderboard skips leaderboard refreshes, the API resyncs its in-memory
+// leaderboards every LEADERBOARD_RESYNC_INTERVAL
+type noLeaderboard struct{}
+
+func (noLeaderboard) Refresh(context.Context, int64, string) {}
+
+func (noLeaderboard) Invalidate(string) {}
+
+func main() {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	file := flag.String("file", "", "CSV or NDJSON file to import, - for stdin")
+	format := flag.String("format", "", "csv or ndjson, detected from the file extension by default")
+	currency := flag.String("currency", entities.DefaultCurrency, "currency of opening balances")
+	batchSize := flag.Int("batch", cfg.ImportBatchSize, "users created per transaction")
+	reportPath := flag.String("report", "", "file to write the JSON report to, stdout by default")
+	flag.Parse()
+
+	if *file == "" || *batchSize <= 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *format == "" {
+		if *format = importFormat(*file); *format == "" {
+			log.Fatalf("Can't detect format of %s, use -format", *file)
+		}
+	}
+
+	var input io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatalf("Failed to open import file: %v", err)
+		}
+		defer f.Close()
+		input = f
+	}
+
+	// Stop between batches on interrupt, committed batches stay imported
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	dbPool, err := initDatabase(ctx, cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer dbPool.Close()
+
+	userRepo := postgresql.NewUserRepository(dbPool)
+	currencyRepo := postgresql.NewCurrencyRepository(dbPool)
+	importUseCase := usecase.NewImportUseCase(userRepo, currencyRepo, noLeaderboard{}, cfg.UsernamePolicy, *batchSize)
+
+	started := time.Now()
+	report, importErr := importUseCase.Import(ctx, input, *format, *currency)
+	if report != nil {
+		if err := writeReport(*reportPath, report); err != nil {
+			log.Printf("Failed to write report: %v", err)
+		}
+		log.Printf("Imported %d of %d users in %s, %d failed", report.Imported, report.Total, time.Since(started).Round(time.Millisecond), report.Failed)
+	}
+	if importErr != nil {
+		log.Fatalf("Import stopped: %v", importErr)
+	}
+}
+
+// importFormat detects the import format from the file extension
+func importFormat(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".csv":
+		return entities.ImportFormatCSV
+	case ".ndjson", ".jsonl":
+		return entities.ImportFormatNDJSON
+	default:
+		return ""
+	}
+}
+
+// writeReport writes the report as indented JSON to the path or stdout
+func writeReport(path string, report *entities.ImportReport) error {
+	out := os.Stdout
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// initDatabase initializes a small database connection pool
+func initDatabase(ctx context.Context, databaseURL string) (*pgxpool.Pool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	poolConfig, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse database URL: %w", err)
+	}
+	poolConfig.MaxConns = 2
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create connection pool: %w", err)
+	}
+
+	// Test connection
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("unable to ping database: %w", err)
+	}
+
+	return pool, nil
+}
@@ -4,15 +4,31 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
 )
 
 // Config holds application configuration
 type Config struct {
-	DatabaseURL   string
-	JWTSecret     string
-	HTTPPort      string
-	ReferralBonus int64
-	RefereeBonus  int64
+	DatabaseURL       string
+	JWTSecret         string
+	HTTPPort          string
+	ReferralBonus     int64
+	RefereeBonus      int64
+	Levels            entities.Levels
+	Timezone          *time.Location
+	LeaderboardResync time.Duration
+	StreakRules       entities.StreakRules
+	StreakFreezePrice int64
+	CallbackMaxSkew   time.Duration
+	DefaultLocale     string
+	UsernamePolicy    *entities.UsernamePolicy
+	DeletionGrace     time.Duration
+	ErasureInterval   time.Duration
+	DataExportTTL     time.Duration
+	ImportBatchSize   int
 }
 
 // Load reads configuration from environment variables
@@ -22,6 +38,8 @@ func Load() (*Config, error) {
 		JWTSecret:   getEnv("JWT_SECRET", "dev_secret_change_in_production"),
 		HTTPPort:    getEnv("HTTP_PORT", "8080"),
 	}
+	// Locale of base task titles and descriptions
+	cfg.DefaultLocale = strings.ToLower(getEnv("DEFAULT_LOCALE", "en"))
 	var err error
 
 	// Parse referral bonus from string to int64
@@ -37,6 +55,86 @@ func Load() (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("invalid REFEREE_BONUS %v", err)
 	}
+
+	// Parse level thresholds for lifetime earned points
+	cfg.Levels, err = entities.ParseLevels(getEnv("LEVEL_THRESHOLDS", "Bronze:0,Silver:500,Gold:2000,Platinum:5000,Diamond:10000"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LEVEL_THRESHOLDS: %v", err)
+	}
+
+	// Load timezone used for daily/weekly/monthly period boundaries
+	cfg.Timezone, err = time.LoadLocation(getEnv("TIMEZONE", "UTC"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TIMEZONE: %v", err)
+	}
+
+	// Parse in-memory leaderboard resync interval
+	cfg.LeaderboardResync, err = time.ParseDuration(getEnv("LEADERBOARD_RESYNC_INTERVAL", "5m"))
+	if err != nil || cfg.LeaderboardResync <= 0 {
+		return nil, fmt.Errorf("invalid LEADERBOARD_RESYNC_INTERVAL: %v", err)
+	}
+
+	// Parse streak reward multipliers and milestone bonuses
+	cfg.StreakRules, err = entities.ParseStreakRules(getEnv("STREAK_MULTIPLIERS", "3:1.5,7:2"), getEnv("STREAK_BONUSES", "7:100,30:500"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid streak rules: %v", err)
+	}
+
+	// Parse streak freeze price in default currency points
+	cfg.StreakFreezePrice, err = strconv.ParseInt(getEnv("STREAK_FREEZE_PRICE", "200"), 10, 64)
+	if err != nil || cfg.StreakFreezePrice < 0 {
+		return nil, fmt.Errorf("invalid STREAK_FREEZE_PRICE: %v", err)
+	}
+
+	// Parse allowed clock skew of partner callback timestamps
+	cfg.CallbackMaxSkew, err = time.ParseDuration(getEnv("PARTNER_CALLBACK_MAX_SKEW", "5m"))
+	if err != nil || cfg.CallbackMaxSkew <= 0 {
+		return nil, fmt.Errorf("invalid PARTNER_CALLBACK_MAX_SKEW: %v", err)
+	}
+
+	// Parse username length, charset, reserved usernames and blocked words
+	minLength, err := strconv.Atoi(getEnv("USERNAME_MIN_LENGTH", "3"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid USERNAME_MIN_LENGTH: %v", err)
+	}
+	maxLength, err := strconv.Atoi(getEnv("USERNAME_MAX_LENGTH", "32"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid USERNAME_MAX_LENGTH: %v", err)
+	}
+	cfg.UsernamePolicy, err = entities.NewUsernamePolicy(
+		minLength,
+		maxLength,
+		strings.ToLower(getEnv("USERNAME_CHARSET", entities.UsernameCharsetASCII)),
+		strings.Split(getEnv("USERNAME_RESERVED", "admin,administrator,root,system,support,help,moderator,staff,official,api,auth,null,undefined,me"), ","),
+		strings.Split(getEnv("USERNAME_BLOCKED_WORDS", ""), ","),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid username policy: %v", err)
+	}
+
+	// Parse how long deleted users can be restored before they are anonymised
+	cfg.DeletionGrace, err = time.ParseDuration(getEnv("ACCOUNT_DELETION_GRACE_PERIOD", "720h"))
+	if err != nil || cfg.DeletionGrace < 0 {
+		return nil, fmt.Errorf("invalid ACCOUNT_DELETION_GRACE_PERIOD: %v", err)
+	}
+
+	// Parse how often deleted users past the grace period are anonymised
+	cfg.ErasureInterval, err = time.ParseDuration(getEnv("ACCOUNT_ERASURE_INTERVAL", "1h"))
+	if err != nil || cfg.ErasureInterval <= 0 {
+		return nil, fmt.Errorf("invalid ACCOUNT_ERASURE_INTERVAL: %v", err)
+	}
+
+	// Parse how long personal data exports can be downloaded
+	cfg.DataExportTTL, err = time.ParseDuration(getEnv("DATA_EXPORT_TTL", "168h"))
+	if err != nil || cfg.DataExportTTL <= 0 {
+		return nil, fmt.Errorf("invalid DATA_EXPORT_TTL: %v", err)
+	}
+
+	// Parse number of users created per bulk import batch
+	cfg.ImportBatchSize, err = strconv.Atoi(getEnv("IMPORT_BATCH_SIZE", "1000"))
+	if err != nil || cfg.ImportBatchSize <= 0 {
+		return nil, fmt.Errorf("invalid IMPORT_BATCH_SIZE: %v", err)
+	}
 	return cfg, nil
 }
 
@@ -0,0 +1,84 @@
+package entities
+
+import "time"
+
+// Account statuses
+const (
+	// UserStatusActive is a user in good standing
+	UserStatusActive = "active"
+	// UserStatusSuspended is a user temporarily restricted by a moderator
+	UserStatusSuspended = "suspended"
+	// UserStatusBanned is a user banned by a moderator
+	UserStatusBanned = "banned"
+	// UserStatusDeleted is reported for soft deleted users, it isn't stored as a status
+	UserStatusDeleted = "deleted"
+)
+
+// AccountStatus is the moderation and deletion state of a user. Restricted and deleted
+// users can't use the API, appear on leaderboards, complete tasks or receive referral rewards
+type AccountStatus struct {
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+	StatusUntil  *time.Time `json:"status_until,omitempty"`
+	Status       string     `json:"status"`
+	StatusReason *string    `json:"status_reason,omitempty"`
+}
+
+// IsRestricted reports whether the user is deleted, suspended or banned at now,
+// a restriction with an expiry ends on its own
+func (s *AccountStatus) IsRestricted(now time.Time) bool {
+	if s.DeletedAt != nil {
+		return true
+	}
+	if s.Status == "" || s.Status == UserStatusActive {
+		return false
+	}
+	return s.StatusUntil == nil || now.Before(*s.StatusUntil)
+}
+
+// CheckAllowed returns UserRestrictedError if the user is restricted at now
+func (s *AccountStatus) CheckAllowed(userID int64, now time.Time) error {
+	if !s.IsRestricted(now) {
+		return nil
+	}
+	if s.DeletedAt != nil {
+		return &UserRestrictedError{UserID: userID, Status: UserStatusDeleted}
+	}
+	return &UserRestrictedError{UserID: userID, Status: s.Status, Reason: s.StatusReason, Until: s.StatusUntil}
+}
+
+// UserStatusChange is an audit record of a moderator changing a user's status
+type UserStatusChange struct {
+	ID             int64      `json:"id"`
+	UserID         int64      `json:"user_id"`
+	ActorID        *int64     `json:"actor_id,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	Status         string     `json:"status"`
+	PreviousStatus string     `json:"previous_status"`
+	Reason         string     `json:"reason"`
+}
+
+// Validate checks a requested status change, restrictions need a reason
+// and an expiry in the future
+func (c *UserStatusChange) Validate(now time.Time) error {
+	switch c.Status {
+	case UserStatusActive:
+		if c.ExpiresAt != nil {
+			return &InvalidStatusChangeError{Reason: "reactivation can't expire"}
+		}
+	case UserStatusSuspended, UserStatusBanned:
+		if c.Reason == "" {
+			return &InvalidStatusChangeError{Reason: "reason is required"}
+		}
+		if c.ExpiresAt != nil && !c.ExpiresAt.After(now) {
+			return &InvalidStatusChangeError{Reason: "expires_at must be in the future"}
+		}
+	default:
+		return &InvalidStatusChangeError{Reason: "status must be active, suspended or banned"}
+	}
+
+	if c.ActorID != nil && *c.ActorID == c.UserID {
+		return &InvalidStatusChangeError{Reason: "can't change own status"}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// Segment is an explicit list of users targeted by tasks
+type Segment struct {
+	ID          int64     `json:"id"`
+	MemberCount int64     `json:"member_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	Code        string    `json:"code"`
+	Title       string    `json:"title"`
+}
+
+// TaskTargeting restricts a task to a cohort of users, unset rules match any user
+type TaskTargeting struct {
+	MinPoints         *int64  `json:"min_points,omitempty"`
+	MinLevel          *int    `json:"min_level,omitempty"`
+	MinAccountAgeDays *int    `json:"min_account_age_days,omitempty"`
+	HasReferrer       *bool   `json:"has_referrer,omitempty"`
+	SegmentIDs        []int64 `json:"segment_ids,omitempty"`
+}
+
+// Audience describes a user the way task targeting rules see them
+type Audience struct {
+	UserID         int64
+	LifetimePoints int64
+	Level          int
+	CreatedAt      time.Time
+	SegmentIDs     []int64
+	HasReferrer    bool
+	AccountStatus  AccountStatus
+}
+
+// Match returns the first targeting rule the audience doesn't satisfy,
+// or an empty string if the audience is targeted
+func (tt *TaskTargeting) Match(a *Audience, now time.Time) string {
+	if tt.MinLevel != nil && a.Level < *tt.MinLevel {
+		return fmt.Sprintf("level %d required", *tt.MinLevel)
+	}
+	if tt.MinPoints != nil && a.LifetimePoints < *tt.MinPoints {
+		return fmt.Sprintf("%d lifetime points required", *tt.MinPoints)
+	}
+	if tt.MinAccountAgeDays != nil && a.CreatedAt.After(now.AddDate(0, 0, -*tt.MinAccountAgeDays)) {
+		return fmt.Sprintf("account must be at least %d days old", *tt.MinAccountAgeDays)
+	}
+	if tt.HasReferrer != nil && a.HasReferrer != *tt.HasReferrer {
+		if *tt.HasReferrer {
+			return "only for invited users"
+		}
+		return "only for users without referrer"
+	}
+	if len(tt.SegmentIDs) > 0 && !containsAny(a.SegmentIDs, tt.SegmentIDs) {
+		return "not in target segment"
+	}
+	return ""
+}
+
+// CheckAudience returns TaskNotTargetedError if the task isn't targeted at the user
+func (t *Task) CheckAudience(a *Audience, now time.Time) error {
+	if reason := t.Targeting.Match(a, now); reason != "" {
+		return &TaskNotTargetedError{TaskID: t.ID, Reason: reason}
+	}
+	return nil
+}
+
+// containsAny reports whether ids contain any of wanted
+func containsAny(ids, wanted []int64) bool {
+	for _, id := range ids {
+		for _, w := range wanted {
+			if id == w {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package entities
+
+import "time"
+
+// Badge rule types
+const (
+	// BadgeRuleTasksCompleted awards a badge after completing Threshold tasks
+	BadgeRuleTasksCompleted = "tasks_completed"
+	// BadgeRuleReferrals awards a badge after referring Threshold users
+	BadgeRuleReferrals = "referrals"
+	// BadgeRuleLeaderboardTop awards a badge for reaching top Threshold of the leaderboard
+	BadgeRuleLeaderboardTop = "leaderboard_top"
+)
+
+// BadgeEvent is a domain event that triggers badge rule evaluation
+type BadgeEvent string
+
+// Badge events
+const (
+	// BadgeEventTaskCompleted is emitted when a user completes a task
+	BadgeEventTaskCompleted BadgeEvent = "task_completed"
+	// BadgeEventReferralAdded is emitted when a user gains a referral
+	BadgeEventReferralAdded BadgeEvent = "referral_added"
+	// BadgeEventPointsChanged is emitted when a user's balance changes
+	BadgeEventPointsChanged BadgeEvent = "points_changed"
+)
+
+// Badge represents an achievement awarded automatically by a rule
+type Badge struct {
+	ID          int64     `json:"id"`
+	Threshold   int64     `json:"threshold"`
+	CreatedAt   time.Time `json:"created_at"`
+	Code        string    `json:"code"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	RuleType    string    `json:"rule_type"`
+	IsActive    bool      `json:"is_active"`
+}
+
+// UserBadge represents a badge earned by a user
+type UserBadge struct {
+	UserID      int64     `json:"user_id"`
+	BadgeID     int64     `json:"badge_id"`
+	AwardedAt   time.Time `json:"awarded_at"`
+	Code        string    `json:"code"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+}
@@ -4,17 +4,21 @@ import (
 	"time"
 )
 
-// Balance - storage of the user's point balance
+// Balance - storage of the user's point balance in a single currency
 type Balance struct {
-	UserID    int64     `json:"user_id"`
-	Points    int64     `json:"points"`
-	UpdatedAt time.Time `json:"updated_at"`
+	UserID         int64     `json:"user_id"`
+	Points         int64     `json:"points"`
+	LifetimePoints int64     `json:"lifetime_points"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	Currency       string    `json:"currency"`
 }
 
 // LeaderboardEntry display in the leaderboard
 type LeaderboardEntry struct {
-	UserID   int64  `json:"user_id"`
-	Points   int64  `json:"points"`
-	Rank     int64  `json:"rank"`
-	Username string `json:"username"`
+	UserID    int64     `json:"user_id"`
+	Points    int64     `json:"points"`
+	Rank      int64     `json:"rank"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Username  string    `json:"username"`
+	Currency  string    `json:"currency"`
 }
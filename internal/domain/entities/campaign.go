@@ -0,0 +1,97 @@
+package entities
+
+import (
+	"math"
+	"time"
+)
+
+// Rewards boosted by campaigns
+const (
+	// CampaignTargetTasks boosts default currency rewards of completed tasks
+	CampaignTargetTasks = "tasks"
+	// CampaignTargetReferrals boosts referral bonuses of both the referrer and the new user
+	CampaignTargetReferrals = "referrals"
+)
+
+// Campaign multiplies rewards for a limited time until its point budget is spent
+type Campaign struct {
+	ID                 int64     `json:"id"`
+	Spent              int64     `json:"spent"`
+	Budget             *int64    `json:"budget,omitempty"`
+	StartsAt           time.Time `json:"starts_at"`
+	EndsAt             time.Time `json:"ends_at"`
+	CreatedAt          time.Time `json:"created_at"`
+	Multiplier         float64   `json:"multiplier"`
+	Code               string    `json:"code"`
+	Title              string    `json:"title"`
+	Categories         []string  `json:"categories,omitempty"`
+	AppliesToTasks     bool      `json:"applies_to_tasks"`
+	AppliesToReferrals bool      `json:"applies_to_referrals"`
+	IsActive           bool      `json:"is_active"`
+}
+
+// IsRunning reports whether the campaign boosts rewards at now
+func (c *Campaign) IsRunning(now time.Time) bool {
+	if !c.IsActive || now.Before(c.StartsAt) || !now.Before(c.EndsAt) {
+		return false
+	}
+	return c.Budget == nil || c.Spent < *c.Budget
+}
+
+// Applies reports whether the campaign boosts rewards of target. Task rewards are boosted
+// only for tasks in the campaign categories if the campaign has any, task is nil for referrals
+func (c *Campaign) Applies(target string, task *Task) bool {
+	switch target {
+	case CampaignTargetTasks:
+		if !c.AppliesToTasks || task == nil {
+			return false
+		}
+		if len(c.Categories) == 0 {
+			return true
+		}
+		if task.Category == nil {
+			return false
+		}
+		for _, code := range c.Categories {
+			if code == task.Category.Code {
+				return true
+			}
+		}
+		return false
+	case CampaignTargetReferrals:
+		return c.AppliesToReferrals
+	default:
+		return false
+	}
+}
+
+// Bonus returns extra points the campaign adds to amount, limited by the remaining budget
+func (c *Campaign) Bonus(amount int64) int64 {
+	bonus := int64(math.Round(float64(amount)*c.Multiplier)) - amount
+	if bonus <= 0 {
+		return 0
+	}
+	if c.Budget != nil {
+		bonus = min(bonus, max(*c.Budget-c.Spent, 0))
+	}
+	return bonus
+}
+
+// Validate checks campaign settings before creation
+func (c *Campaign) Validate() error {
+	switch {
+	case c.Code == "" || c.Title == "":
+		return &InvalidCampaignError{Reason: "code and title are required"}
+	case c.Multiplier <= 1:
+		return &InvalidCampaignError{Reason: "multiplier must be greater than 1"}
+	case !c.EndsAt.After(c.StartsAt):
+		return &InvalidCampaignError{Reason: "ends_at must be after starts_at"}
+	case c.Budget != nil && *c.Budget <= 0:
+		return &InvalidCampaignError{Reason: "budget must be positive"}
+	case !c.AppliesToTasks && !c.AppliesToReferrals:
+		return &InvalidCampaignError{Reason: "campaign must apply to tasks or referrals"}
+	case len(c.Categories) > 0 && !c.AppliesToTasks:
+		return &InvalidCampaignError{Reason: "categories require a campaign applying to tasks"}
+	}
+	return nil
+}
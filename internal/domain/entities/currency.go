@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+// DefaultCurrency is the currency used when no currency is specified
+const DefaultCurrency = "points"
+
+// Currency represents a kind of points users can earn
+type Currency struct {
+	CreatedAt   time.Time `json:"created_at"`
+	Code        string    `json:"code"`
+	Title       string    `json:"title"`
+	IsSpendable bool      `json:"is_spendable"`
+}
@@ -1,6 +1,14 @@
 package entities
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
 	"time"
 )
@@ -127,3 +135,1134 @@ func TestTransaction_NegativeDelta(t *testing.T) {
 		t.Error("Expected nil ReferenceID")
 	}
 }
+
+func TestTask_RewardsIncludeAllCurrencies(t *testing.T) {
+	task := &Task{
+		ID:           1,
+		RewardPoints: 100,
+		ExtraRewards: []TaskReward{{TaskID: 1, Currency: "xp", Amount: 30}},
+	}
+
+	rewards := task.Rewards()
+	if len(rewards) != 2 {
+		t.Fatalf("Expected 2 rewards, got %d", len(rewards))
+	}
+
+	if rewards[0].Currency != DefaultCurrency || rewards[0].Amount != 100 {
+		t.Errorf("Expected default currency reward of 100, got %s %d", rewards[0].Currency, rewards[0].Amount)
+	}
+
+	if rewards[1].Currency != "xp" || rewards[1].Amount != 30 {
+		t.Errorf("Expected xp reward of 30, got %s %d", rewards[1].Currency, rewards[1].Amount)
+	}
+}
+
+func TestTask_RewardsWithoutDefaultCurrency(t *testing.T) {
+	task := &Task{
+		ID:           2,
+		ExtraRewards: []TaskReward{{TaskID: 2, Currency: "xp", Amount: 10}},
+	}
+
+	rewards := task.Rewards()
+	if len(rewards) != 1 {
+		t.Fatalf("Expected 1 reward, got %d", len(rewards))
+	}
+
+	if rewards[0].Currency != "xp" {
+		t.Errorf("Expected xp reward, got %s", rewards[0].Currency)
+	}
+}
+
+func TestTask_CheckAvailable(t *testing.T) {
+	now := time.Date(2025, 11, 7, 12, 0, 0, 0, time.UTC)
+	before := now.Add(-time.Hour)
+	after := now.Add(time.Hour)
+	limit := int64(1000)
+
+	tests := []struct {
+		name string
+		task Task
+		want error
+	}{
+		{"open-ended", Task{ID: 1}, nil},
+		{"within window", Task{ID: 1, StartsAt: &before, EndsAt: &after}, nil},
+		{"not started", Task{ID: 1, StartsAt: &after}, &TaskNotStartedError{}},
+		{"expired", Task{ID: 1, EndsAt: &before}, &TaskExpiredError{}},
+		{"ends now", Task{ID: 1, EndsAt: &now}, &TaskExpiredError{}},
+		{"slots left", Task{ID: 1, MaxCompletions: &limit, Completions: 999}, nil},
+		{"cap reached", Task{ID: 1, MaxCompletions: &limit, Completions: 1000}, &TaskCapReachedError{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.task.CheckAvailable(now)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Expected task to be available, got %v", err)
+				}
+				return
+			}
+			if fmt.Sprintf("%T", err) != fmt.Sprintf("%T", tt.want) {
+				t.Errorf("Expected %T, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestTask_Remaining(t *testing.T) {
+	task := &Task{Completions: 5}
+	if task.Remaining() != nil {
+		t.Error("Expected no remaining slots limit for uncapped task")
+	}
+
+	limit := int64(3)
+	task.MaxCompletions = &limit
+	if remaining := task.Remaining(); remaining == nil || *remaining != 0 {
+		t.Errorf("Expected 0 remaining slots when completions exceed the cap, got %v", remaining)
+	}
+}
+
+func TestTask_CheckUserLimit(t *testing.T) {
+	task := &Task{ID: 3}
+	if err := task.CheckUserLimit(1, 100); err != nil {
+		t.Errorf("Expected no per-user limit, got %v", err)
+	}
+
+	limit := 2
+	task.MaxPerUser = &limit
+	if err := task.CheckUserLimit(1, 1); err != nil {
+		t.Errorf("Expected second completion to be allowed, got %v", err)
+	}
+
+	var limitErr *TaskUserLimitReachedError
+	if err := task.CheckUserLimit(1, 2); !errors.As(err, &limitErr) {
+		t.Errorf("Expected TaskUserLimitReachedError, got %v", err)
+	}
+}
+
+func TestTask_MissingPrerequisites(t *testing.T) {
+	task := &Task{ID: 3, Prerequisites: []int64{1, 2}}
+
+	missing := task.MissingPrerequisites([]int64{2, 5})
+	if len(missing) != 1 || missing[0] != 1 {
+		t.Errorf("Expected missing prerequisite 1, got %v", missing)
+	}
+
+	if missing := task.MissingPrerequisites([]int64{1, 2}); len(missing) != 0 {
+		t.Errorf("Expected task to be unlocked, got missing %v", missing)
+	}
+}
+
+func TestTask_ValidateProof(t *testing.T) {
+	manual := &Task{ID: 4, Verification: TaskVerificationManual}
+	auto := &Task{ID: 5, Verification: TaskVerificationAuto}
+
+	tests := []struct {
+		name    string
+		task    *Task
+		url     string
+		text    string
+		wantErr bool
+	}{
+		{"auto without proof", auto, "", "", false},
+		{"manual without proof", manual, "", "  ", true},
+		{"manual with url", manual, "https://example.com/post/1", "", false},
+		{"manual with text", manual, "", "shared in my channel", false},
+		{"relative url", manual, "/post/1", "", true},
+		{"unsupported scheme", manual, "javascript:alert(1)", "", true},
+		{"too long text", manual, "", strings.Repeat("a", MaxProofTextLength+1), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.task.ValidateProof(tt.url, tt.text)
+			var proofErr *InvalidProofError
+			if tt.wantErr != errors.As(err, &proofErr) {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestTask_RequiresVerification(t *testing.T) {
+	for verification, want := range map[string]bool{
+		TaskVerificationAuto:     false,
+		TaskVerificationManual:   true,
+		TaskVerificationCallback: true,
+	} {
+		task := &Task{Verification: verification}
+		if got := task.RequiresVerification(); got != want {
+			t.Errorf("Expected RequiresVerification %v for %s, got %v", want, verification, got)
+		}
+	}
+}
+
+func TestPartnerCallback_Validate(t *testing.T) {
+	now := time.Date(2025, 11, 7, 12, 0, 0, 0, time.UTC)
+	valid := PartnerCallback{UserID: 1, TaskCode: "TASK_PARTNER", Nonce: "abc", Timestamp: now.Unix()}
+
+	tests := []struct {
+		name    string
+		modify  func(c *PartnerCallback)
+		wantErr bool
+	}{
+		{"valid", func(c *PartnerCallback) {}, false},
+		{"slightly in the future", func(c *PartnerCallback) { c.Timestamp = now.Add(time.Minute).Unix() }, false},
+		{"too old", func(c *PartnerCallback) { c.Timestamp = now.Add(-10 * time.Minute).Unix() }, true},
+		{"too far in the future", func(c *PartnerCallback) { c.Timestamp = now.Add(10 * time.Minute).Unix() }, true},
+		{"missing user", func(c *PartnerCallback) { c.UserID = 0 }, true},
+		{"missing task code", func(c *PartnerCallback) { c.TaskCode = " " }, true},
+		{"missing nonce", func(c *PartnerCallback) { c.Nonce = "" }, true},
+		{"long nonce", func(c *PartnerCallback) { c.Nonce = strings.Repeat("n", MaxNonceLength+1) }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callback := valid
+			tt.modify(&callback)
+
+			err := callback.Validate(now, 5*time.Minute)
+			var callbackErr *InvalidCallbackError
+			if tt.wantErr != errors.As(err, &callbackErr) {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"", nil},
+		{"ru", []string{"ru"}},
+		{"en;q=0.5, ru-RU, ru;q=0.9", []string{"ru-ru", "ru", "en"}},
+		{"pt-BR,de;q=0,*;q=0.1", []string{"pt-br", "pt"}},
+		{"fr;q=bad, es", []string{"es"}},
+	}
+
+	for _, tt := range tests {
+		got := ParseAcceptLanguage(tt.header)
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("ParseAcceptLanguage(%q) = %v, expected %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestTask_Localize(t *testing.T) {
+	translations := []*TaskTranslation{
+		{TaskID: 1, Locale: "ru", Title: "Заполните профиль", Description: "Укажите данные"},
+		{TaskID: 2, Locale: "de", Title: "Profil ausfüllen"},
+	}
+
+	tests := []struct {
+		name      string
+		locales   []string
+		wantTitle string
+		wantLoc   string
+	}{
+		{"preferred translation", []string{"ru-ru", "ru", "en"}, "Заполните профиль", "ru"},
+		{"default preferred first", []string{"en", "ru"}, "Complete Profile", "en"},
+		{"no translation", []string{"fr"}, "Complete Profile", "en"},
+		{"no preference", nil, "Complete Profile", "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{ID: 1, Title: "Complete Profile", Description: "Fill in your profile"}
+			task.Localize(translations, tt.locales, "en")
+
+			if task.Title != tt.wantTitle || task.Locale != tt.wantLoc {
+				t.Errorf("Expected %q in %s, got %q in %s", tt.wantTitle, tt.wantLoc, task.Title, task.Locale)
+			}
+		})
+	}
+}
+
+func TestTaskFilter_Matches(t *testing.T) {
+	task := &Task{
+		Category: &TaskCategory{Code: "social"},
+		Tags:     []string{"social", "daily"},
+	}
+
+	tests := []struct {
+		name   string
+		filter TaskFilter
+		want   bool
+	}{
+		{"empty filter", TaskFilter{}, true},
+		{"matching category", TaskFilter{Category: "Social"}, true},
+		{"other category", TaskFilter{Category: "daily"}, false},
+		{"matching tag", TaskFilter{Tag: "daily"}, true},
+		{"missing tag", TaskFilter{Tag: "referral"}, false},
+		{"category and tag", TaskFilter{Category: "social", Tag: "daily"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(task); got != tt.want {
+				t.Errorf("Matches() = %v, expected %v", got, tt.want)
+			}
+		})
+	}
+
+	if (TaskFilter{Category: "social"}).Matches(&Task{}) {
+		t.Error("Expected task without category not to match category filter")
+	}
+}
+
+func TestTask_CheckAudience(t *testing.T) {
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	level, points, days := 2, int64(500), 7
+	withReferrer, withoutReferrer := true, false
+
+	audience := &Audience{
+		UserID:         1,
+		LifetimePoints: 600,
+		Level:          2,
+		CreatedAt:      now.AddDate(0, 0, -10),
+		SegmentIDs:     []int64{3},
+		HasReferrer:    true,
+	}
+
+	tests := []struct {
+		name      string
+		targeting TaskTargeting
+		wantErr   bool
+	}{
+		{"no rules", TaskTargeting{}, false},
+		{"level reached", TaskTargeting{MinLevel: &level}, false},
+		{"points reached", TaskTargeting{MinPoints: &points}, false},
+		{"account old enough", TaskTargeting{MinAccountAgeDays: &days}, false},
+		{"has referrer", TaskTargeting{HasReferrer: &withReferrer}, false},
+		{"no referrer required", TaskTargeting{HasReferrer: &withoutReferrer}, true},
+		{"in segment", TaskTargeting{SegmentIDs: []int64{2, 3}}, false},
+		{"not in segment", TaskTargeting{SegmentIDs: []int64{4}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{ID: 1, Targeting: tt.targeting}
+			err := task.CheckAudience(audience, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckAudience() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var targetErr *TaskNotTargetedError
+			if err != nil && !errors.As(err, &targetErr) {
+				t.Errorf("Expected TaskNotTargetedError, got %T", err)
+			}
+		})
+	}
+
+	newcomer := *audience
+	newcomer.Level = 1
+	newcomer.LifetimePoints = 100
+	newcomer.CreatedAt = now.AddDate(0, 0, -1)
+	for _, targeting := range []TaskTargeting{{MinLevel: &level}, {MinPoints: &points}, {MinAccountAgeDays: &days}} {
+		task := &Task{ID: 1, Targeting: targeting}
+		if err := task.CheckAudience(&newcomer, now); err == nil {
+			t.Errorf("Expected newcomer to be excluded by %+v", targeting)
+		}
+	}
+}
+
+func TestCampaign_Bonus(t *testing.T) {
+	budget := int64(150)
+
+	tests := []struct {
+		name     string
+		campaign Campaign
+		amount   int64
+		want     int64
+	}{
+		{"double points", Campaign{Multiplier: 2}, 100, 100},
+		{"rounded", Campaign{Multiplier: 1.5}, 25, 13},
+		{"limited by budget", Campaign{Multiplier: 2, Budget: &budget, Spent: 100}, 100, 50},
+		{"budget spent", Campaign{Multiplier: 2, Budget: &budget, Spent: 150}, 100, 0},
+		{"no amount", Campaign{Multiplier: 2}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.campaign.Bonus(tt.amount); got != tt.want {
+				t.Errorf("Bonus(%d) = %d, expected %d", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCampaign_Applies(t *testing.T) {
+	social := &Task{Category: &TaskCategory{Code: "social"}}
+	daily := &Task{Category: &TaskCategory{Code: "daily"}}
+	uncategorized := &Task{}
+
+	all := Campaign{AppliesToTasks: true}
+	scoped := Campaign{AppliesToTasks: true, Categories: []string{"social"}}
+	referrals := Campaign{AppliesToReferrals: true}
+
+	tests := []struct {
+		name     string
+		campaign Campaign
+		target   string
+		task     *Task
+		want     bool
+	}{
+		{"all tasks", all, CampaignTargetTasks, uncategorized, true},
+		{"category match", scoped, CampaignTargetTasks, social, true},
+		{"other category", scoped, CampaignTargetTasks, daily, false},
+		{"uncategorized task", scoped, CampaignTargetTasks, uncategorized, false},
+		{"tasks campaign on referral", all, CampaignTargetReferrals, nil, false},
+		{"referral campaign", referrals, CampaignTargetReferrals, nil, true},
+		{"referral campaign on task", referrals, CampaignTargetTasks, social, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.campaign.Applies(tt.target, tt.task); got != tt.want {
+				t.Errorf("Applies() = %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCampaign_IsRunning(t *testing.T) {
+	now := time.Date(2024, 6, 8, 12, 0, 0, 0, time.UTC)
+	budget := int64(1000)
+	campaign := Campaign{
+		StartsAt: now.Add(-time.Hour),
+		EndsAt:   now.Add(time.Hour),
+		Budget:   &budget,
+		IsActive: true,
+	}
+
+	if !campaign.IsRunning(now) {
+		t.Error("Expected campaign to be running")
+	}
+	if campaign.IsRunning(now.Add(time.Hour)) {
+		t.Error("Expected campaign to be over at ends_at")
+	}
+	if campaign.IsRunning(now.Add(-2 * time.Hour)) {
+		t.Error("Expected campaign not to be started")
+	}
+
+	campaign.Spent = budget
+	if campaign.IsRunning(now) {
+		t.Error("Expected campaign with spent budget not to be running")
+	}
+}
+
+func TestCampaign_Validate(t *testing.T) {
+	now := time.Now()
+	valid := Campaign{Code: "DOUBLE", Title: "Double points", Multiplier: 2, StartsAt: now, EndsAt: now.Add(48 * time.Hour), AppliesToTasks: true}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Expected valid campaign, got %v", err)
+	}
+
+	invalid := []func(c *Campaign){
+		func(c *Campaign) { c.Code = "" },
+		func(c *Campaign) { c.Multiplier = 1 },
+		func(c *Campaign) { c.EndsAt = c.StartsAt },
+		func(c *Campaign) { c.Budget = new(int64) },
+		func(c *Campaign) { c.AppliesToTasks = false },
+		func(c *Campaign) {
+			c.AppliesToTasks, c.AppliesToReferrals, c.Categories = false, true, []string{"social"}
+		},
+	}
+	for i, modify := range invalid {
+		campaign := valid
+		modify(&campaign)
+		var invalidErr *InvalidCampaignError
+		if err := campaign.Validate(); !errors.As(err, &invalidErr) {
+			t.Errorf("case %d: expected InvalidCampaignError, got %v", i, err)
+		}
+	}
+}
+
+func TestParseLevels(t *testing.T) {
+	levels, err := ParseLevels("Silver:500, Bronze:0,Gold:2000")
+	if err != nil {
+		t.Fatalf("Failed to parse levels: %v", err)
+	}
+
+	if len(levels) != 3 {
+		t.Fatalf("Expected 3 levels, got %d", len(levels))
+	}
+
+	if levels[0].Name != "Bronze" || levels[0].Number != 1 {
+		t.Errorf("Expected Bronze as level 1, got %s as level %d", levels[0].Name, levels[0].Number)
+	}
+
+	if levels[2].Name != "Gold" || levels[2].Number != 3 {
+		t.Errorf("Expected Gold as level 3, got %s as level %d", levels[2].Name, levels[2].Number)
+	}
+}
+
+func TestParseLevels_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "Bronze", "Bronze:abc", "Bronze:-1", "Bronze:0,Silver:0"} {
+		if _, err := ParseLevels(spec); err == nil {
+			t.Errorf("Expected error for spec %q", spec)
+		}
+	}
+}
+
+func TestLevels_Progress(t *testing.T) {
+	levels, _ := ParseLevels("Bronze:0,Silver:500,Gold:2000")
+
+	progress := levels.Progress(1250)
+	if progress.Name != "Silver" {
+		t.Errorf("Expected Silver, got %s", progress.Name)
+	}
+
+	if progress.NextLevel == nil || progress.NextLevel.Name != "Gold" {
+		t.Fatal("Expected Gold as next level")
+	}
+
+	if progress.PointsToNextLevel != 750 {
+		t.Errorf("Expected 750 points to next level, got %d", progress.PointsToNextLevel)
+	}
+
+	if progress.Progress != 0.5 {
+		t.Errorf("Expected progress 0.5, got %f", progress.Progress)
+	}
+
+	top := levels.Progress(5000)
+	if top.Name != "Gold" || top.NextLevel != nil || top.Progress != 1 {
+		t.Errorf("Expected max level Gold with full progress, got %s %f", top.Name, top.Progress)
+	}
+}
+
+func TestLeaderboardWindow_Start(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	// Wednesday 2025-11-05 22:30 UTC is Thursday 01:30 in UTC+3
+	now := time.Date(2025, 11, 5, 22, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		window LeaderboardWindow
+		want   time.Time
+	}{
+		{LeaderboardWindowDaily, time.Date(2025, 11, 6, 0, 0, 0, 0, loc)},
+		{LeaderboardWindowWeekly, time.Date(2025, 11, 3, 0, 0, 0, 0, loc)},
+		{LeaderboardWindowMonthly, time.Date(2025, 11, 1, 0, 0, 0, 0, loc)},
+	}
+
+	for _, tt := range tests {
+		got, ok := tt.window.Start(now, loc)
+		if !ok {
+			t.Fatalf("Expected %s window to have a start", tt.window)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Expected %s window to start at %v, got %v", tt.window, tt.want, got)
+		}
+	}
+
+	if _, ok := LeaderboardWindowAllTime.Start(now, loc); ok {
+		t.Error("Expected all_time window to have no start")
+	}
+}
+
+func TestParseLeaderboardWindow(t *testing.T) {
+	if w, err := ParseLeaderboardWindow("weekly"); err != nil || w != LeaderboardWindowWeekly {
+		t.Errorf("Expected weekly window, got %q (%v)", w, err)
+	}
+
+	if _, err := ParseLeaderboardWindow("yearly"); err == nil {
+		t.Error("Expected error for unknown window")
+	}
+}
+
+func TestParseRankingMode(t *testing.T) {
+	if m, err := ParseRankingMode(""); err != nil || m != RankingCompetition {
+		t.Errorf("Expected competition ranking by default, got %q (%v)", m, err)
+	}
+
+	if m, err := ParseRankingMode("dense"); err != nil || m != RankingDense {
+		t.Errorf("Expected dense ranking, got %q (%v)", m, err)
+	}
+
+	if _, err := ParseRankingMode("ordinal"); err == nil {
+		t.Error("Expected error for unknown ranking mode")
+	}
+}
+
+func TestStreak_Advance(t *testing.T) {
+	day := 24 * time.Hour
+	start := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	streak := &Streak{}
+
+	streak.Advance(start, day, 0)
+	streak.Advance(start.Add(day), day, 0)
+	if streak.Current != 2 || streak.Longest != 2 {
+		t.Fatalf("Expected streak 2/2, got %d/%d", streak.Current, streak.Longest)
+	}
+
+	// One missed day bridged by a freeze
+	if used := streak.Advance(start.Add(3*day), day, 1); used != 1 {
+		t.Errorf("Expected 1 freeze used, got %d", used)
+	}
+	if streak.Current != 3 {
+		t.Errorf("Expected streak 3 after freeze, got %d", streak.Current)
+	}
+
+	// Two missed days without freezes reset the streak
+	if used := streak.Advance(start.Add(6*day), day, 1); used != 0 {
+		t.Errorf("Expected no freezes used, got %d", used)
+	}
+	if streak.Current != 1 || streak.Longest != 3 {
+		t.Errorf("Expected streak 1/3 after reset, got %d/%d", streak.Current, streak.Longest)
+	}
+}
+
+func TestParseStreakRules(t *testing.T) {
+	rules, err := ParseStreakRules("7:2, 3:1.5", "7:100,30:500")
+	if err != nil {
+		t.Fatalf("Failed to parse streak rules: %v", err)
+	}
+
+	if m := rules.Multiplier(2); m != 1 {
+		t.Errorf("Expected multiplier 1 for streak 2, got %f", m)
+	}
+	if m := rules.Multiplier(5); m != 1.5 {
+		t.Errorf("Expected multiplier 1.5 for streak 5, got %f", m)
+	}
+	if m := rules.Multiplier(10); m != 2 {
+		t.Errorf("Expected multiplier 2 for streak 10, got %f", m)
+	}
+
+	if b := rules.Bonus(7); b != 100 {
+		t.Errorf("Expected bonus 100 for streak 7, got %d", b)
+	}
+	if b := rules.Bonus(8); b != 0 {
+		t.Errorf("Expected no bonus for streak 8, got %d", b)
+	}
+
+	if _, err := ParseStreakRules("3:0.5", ""); err == nil {
+		t.Error("Expected error for multiplier below 1")
+	}
+}
+
+func TestProfilePatchApply(t *testing.T) {
+	name := "Alice"
+	user := &User{ID: 1, Username: "alice"}
+	user.DisplayName = &name
+	user.Attributes = map[string]any{"theme": "dark", "links": map[string]any{"github": "alice", "x": "alice"}}
+
+	patch, err := ParseProfilePatch([]byte(`{
+		"username": "alice_w",
+		"display_name": null,
+		"email": "Alice@Example.com",
+		"timezone": "Europe/Berlin",
+		"attributes": {"theme": null, "links": {"x": null, "site": "https://alice.dev"}}
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to parse patch: %v", err)
+	}
+	if err := patch.Apply(user); err != nil {
+		t.Fatalf("Failed to apply patch: %v", err)
+	}
+
+	if user.Username != "alice_w" {
+		t.Errorf("Expected username alice_w, got %s", user.Username)
+	}
+	if user.DisplayName != nil {
+		t.Errorf("Expected display name to be removed, got %q", *user.DisplayName)
+	}
+	if user.Email == nil || *user.Email != "alice@example.com" {
+		t.Errorf("Expected lower case email, got %v", user.Email)
+	}
+	if _, ok := user.Attributes["theme"]; ok {
+		t.Error("Expected theme attribute to be removed")
+	}
+	links, _ := user.Attributes["links"].(map[string]any)
+	if len(links) != 2 || links["github"] != "alice" || links["site"] != "https://alice.dev" {
+		t.Errorf("Expected merged links, got %v", links)
+	}
+}
+
+func TestProfilePatchValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		field string
+	}{
+		{"not an object", `[]`, ""},
+		{"unknown field", `{"role": "admin"}`, "role"},
+		{"null username", `{"username": null}`, "username"},
+		{"invalid email", `{"email": "alice"}`, "email"},
+		{"avatar scheme", `{"avatar_url": "javascript:alert(1)"}`, "avatar_url"},
+		{"locale", `{"locale": "english"}`, "locale"},
+		{"timezone", `{"timezone": "Mars/Olympus"}`, "timezone"},
+		{"attributes type", `{"attributes": 1}`, "attributes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := ParseProfilePatch([]byte(tt.patch))
+			if err == nil {
+				err = patch.Apply(&User{Username: "alice"})
+			}
+
+			var profileErr *InvalidProfileError
+			if !errors.As(err, &profileErr) {
+				t.Fatalf("Expected InvalidProfileError, got %v", err)
+			}
+			if profileErr.Field != tt.field {
+				t.Errorf("Expected field %q, got %q", tt.field, profileErr.Field)
+			}
+		})
+	}
+}
+
+func TestUsernamePolicy(t *testing.T) {
+	policy, err := NewUsernamePolicy(3, 16, UsernameCharsetASCII, []string{"admin", "support"}, []string{"badword"})
+	if err != nil {
+		t.Fatalf("Failed to create policy: %v", err)
+	}
+	unicodePolicy, err := NewUsernamePolicy(3, 16, UsernameCharsetUnicode, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create policy: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		policy   *UsernamePolicy
+		username string
+		rules    []string
+	}{
+		{"valid", policy, "alice_w.92", nil},
+		{"too short", policy, "al", []string{UsernameRuleLength}},
+		{"too long", policy, strings.Repeat("a", 17), []string{UsernameRuleLength}},
+		{"whitespace", policy, "alice w", []string{UsernameRuleCharset}},
+		{"non ascii", policy, "алиса", []string{UsernameRuleCharset}},
+		{"leading separator", policy, "_alice", []string{UsernameRuleSeparators}},
+		{"consecutive separators", policy, "al..ice", []string{UsernameRuleSeparators}},
+		{"reserved", policy, "Admin", []string{UsernameRuleReserved}},
+		{"reserved with separators", policy, "sup.port", []string{UsernameRuleReserved}},
+		{"reserved look-alike", policy, "supp0rt", []string{UsernameRuleReserved}},
+		{"blocked", policy, "mybadword1", []string{UsernameRuleBlocked}},
+		{"unicode", unicodePolicy, "алиса", nil},
+		{"mixed script", unicodePolicy, "pаypal", []string{UsernameRuleMixedScript}},
+		{"several rules", policy, "_a d", []string{UsernameRuleCharset, UsernameRuleSeparators}},
+		{"several rules with length", policy, "_ ", []string{UsernameRuleLength, UsernameRuleCharset, UsernameRuleSeparators}},
+		{"several rules with blocked", policy, "-badword!", []string{UsernameRuleCharset, UsernameRuleSeparators, UsernameRuleBlocked}},
+		{"empty", policy, "", []string{UsernameRuleLength, UsernameRuleSeparators}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate(tt.username)
+			if tt.rules == nil {
+				if err != nil {
+					t.Fatalf("Expected valid username, got %v", err)
+				}
+				return
+			}
+
+			var usernameErr *InvalidUsernameError
+			if !errors.As(err, &usernameErr) {
+				t.Fatalf("Expected InvalidUsernameError, got %v", err)
+			}
+			var rules []string
+			for _, v := range usernameErr.Violations {
+				rules = append(rules, v.Rule)
+			}
+			if strings.Join(rules, ",") != strings.Join(tt.rules, ",") {
+				t.Errorf("Expected rules %v, got %v", tt.rules, rules)
+			}
+		})
+	}
+
+	if _, err := NewUsernamePolicy(3, 300, UsernameCharsetASCII, nil, nil); err == nil {
+		t.Error("Expected error for max length above column size")
+	}
+}
+
+func TestNormalizeUsername(t *testing.T) {
+	same := []string{"bob", "Bob", "BOB", "b0b", "ｂｏｂ", "bоb"}
+	for _, username := range same {
+		if got := NormalizeUsername(username); got != "bob" {
+			t.Errorf("Expected %q to normalize to bob, got %q", username, got)
+		}
+	}
+
+	if NormalizeUsername("bob_1") == NormalizeUsername("bob.1") {
+		t.Error("Expected separators to be kept")
+	}
+}
+
+func TestAccountStatus(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	reason := "spam"
+
+	tests := []struct {
+		name       string
+		status     AccountStatus
+		restricted bool
+	}{
+		{"active", AccountStatus{Status: UserStatusActive}, false},
+		{"suspended", AccountStatus{Status: UserStatusSuspended, StatusReason: &reason, StatusUntil: &future}, true},
+		{"suspension expired", AccountStatus{Status: UserStatusSuspended, StatusUntil: &past}, false},
+		{"banned forever", AccountStatus{Status: UserStatusBanned, StatusReason: &reason}, true},
+		{"deleted", AccountStatus{Status: UserStatusActive, DeletedAt: &past}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.status.IsRestricted(now); got != tt.restricted {
+				t.Errorf("Expected restricted %v, got %v", tt.restricted, got)
+			}
+
+			err := tt.status.CheckAllowed(1, now)
+			var restrictedErr *UserRestrictedError
+			if errors.As(err, &restrictedErr) != tt.restricted {
+				t.Errorf("Expected UserRestrictedError %v, got %v", tt.restricted, err)
+			}
+		})
+	}
+}
+
+func TestUserStatusChangeValidate(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	moderatorID := int64(2)
+	userID := int64(1)
+
+	tests := []struct {
+		name   string
+		change UserStatusChange
+		valid  bool
+	}{
+		{"suspend", UserStatusChange{UserID: 1, ActorID: &moderatorID, Status: UserStatusSuspended, Reason: "spam", ExpiresAt: &future}, true},
+		{"ban", UserStatusChange{UserID: 1, ActorID: &moderatorID, Status: UserStatusBanned, Reason: "fraud"}, true},
+		{"reactivate", UserStatusChange{UserID: 1, ActorID: &moderatorID, Status: UserStatusActive}, true},
+		{"no reason", UserStatusChange{UserID: 1, Status: UserStatusBanned}, false},
+		{"expired", UserStatusChange{UserID: 1, Status: UserStatusSuspended, Reason: "spam", ExpiresAt: &past}, false},
+		{"expiring reactivation", UserStatusChange{UserID: 1, Status: UserStatusActive, ExpiresAt: &future}, false},
+		{"unknown status", UserStatusChange{UserID: 1, Status: "deleted", Reason: "spam"}, false},
+		{"own status", UserStatusChange{UserID: 1, ActorID: &userID, Status: UserStatusBanned, Reason: "spam"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.change.Validate(now)
+			if tt.valid && err != nil {
+				t.Errorf("Expected valid change, got %v", err)
+			}
+			var invalidErr *InvalidStatusChangeError
+			if !tt.valid && !errors.As(err, &invalidErr) {
+				t.Errorf("Expected InvalidStatusChangeError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAnonymizedUsername(t *testing.T) {
+	policy, err := NewUsernamePolicy(3, 32, UsernameCharsetUnicode, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create policy: %v", err)
+	}
+
+	username := AnonymizedUsername(42)
+	if err := policy.Validate(username); err == nil {
+		t.Errorf("Expected anonymized username %q to break the username policy", username)
+	}
+	if AnonymizedUsername(1) == AnonymizedUsername(2) {
+		t.Error("Expected anonymized usernames to be unique")
+	}
+}
+
+func TestDataExportIsExpired(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		export  DataExport
+		expired bool
+	}{
+		{"pending", DataExport{Status: DataExportStatusPending}, false},
+		{"ready", DataExport{Status: DataExportStatusReady, ExpiresAt: &future}, false},
+		{"past expiry", DataExport{Status: DataExportStatusReady, ExpiresAt: &past}, true},
+		{"expired", DataExport{Status: DataExportStatusExpired}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.export.IsExpired(now); got != tt.expired {
+				t.Errorf("Expected expired %v, got %v", tt.expired, got)
+			}
+		})
+	}
+}
+
+func TestPersonalDataArchive(t *testing.T) {
+	email := "alice@example.com"
+	data := &PersonalData{
+		ExportedAt:   time.Now(),
+		User:         &User{ID: 1, Username: "alice", Profile: Profile{Email: &email}, CreatedAt: time.Now()},
+		Balances:     []*Balance{{UserID: 1, Currency: "points", Points: 100}},
+		Transactions: []*Transaction{{ID: 1, UserID: 1, Currency: "points", Delta: 100, Reason: "task"}},
+		Referrals:    []*Referral{{UserID: 2, Username: "bob"}},
+	}
+
+	content, err := data.Archive(DataExportFormatJSON)
+	if err != nil {
+		t.Fatalf("Failed to build JSON archive: %v", err)
+	}
+	var decoded PersonalData
+	if err := json.Unmarshal(content, &decoded); err != nil {
+		t.Fatalf("Failed to decode JSON archive: %v", err)
+	}
+	if decoded.User.Username != "alice" || len(decoded.Transactions) != 1 || len(decoded.Referrals) != 1 {
+		t.Errorf("Unexpected JSON archive: %s", content)
+	}
+
+	content, err = data.Archive(DataExportFormatZIP)
+	if err != nil {
+		t.Fatalf("Failed to build zip archive: %v", err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatalf("Failed to read zip archive: %v", err)
+	}
+	files := make(map[string]*zip.File)
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+	for _, name := range []string{"user.csv", "balances.csv", "transactions.csv", "tasks.csv", "referrals.csv", "username_history.csv", "data.json"} {
+		if files[name] == nil {
+			t.Errorf("Expected %s in zip archive", name)
+		}
+	}
+
+	f, err := files["user.csv"].Open()
+	if err != nil {
+		t.Fatalf("Failed to open user.csv: %v", err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read user.csv: %v", err)
+	}
+	found := false
+	for _, record := range records {
+		if record[0] == "email" && record[1] == email {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected email in user.csv, got %v", records)
+	}
+
+	var formatErr *InvalidExportFormatError
+	if _, err := data.Archive("xml"); !errors.As(err, &formatErr) {
+		t.Errorf("Expected InvalidExportFormatError, got %v", err)
+	}
+}
+
+func TestUserSearchValidate(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+	low, high := int64(10), int64(100)
+
+	valid := func() UserSearch {
+		return UserSearch{Limit: 50, Match: UserMatchPrefix, Sort: UserSortCreatedAt, Descending: true}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(s *UserSearch)
+		valid  bool
+	}{
+		{"defaults", func(s *UserSearch) {}, true},
+		{"filters", func(s *UserSearch) {
+			s.CreatedFrom, s.CreatedTo, s.MinBalance, s.MaxBalance, s.Status = &earlier, &now, &low, &high, UserStatusBanned
+		}, true},
+		{"unknown match", func(s *UserSearch) { s.Match = "regex" }, false},
+		{"unknown sort", func(s *UserSearch) { s.Sort = "email" }, false},
+		{"unknown status", func(s *UserSearch) { s.Status = "pending" }, false},
+		{"zero limit", func(s *UserSearch) { s.Limit = 0 }, false},
+		{"large limit", func(s *UserSearch) { s.Limit = MaxUserSearchLimit + 1 }, false},
+		{"reversed dates", func(s *UserSearch) { s.CreatedFrom, s.CreatedTo = &now, &earlier }, false},
+		{"reversed balances", func(s *UserSearch) { s.MinBalance, s.MaxBalance = &high, &low }, false},
+		{"cursor of same sort", func(s *UserSearch) {
+			s.After = &UserSearchCursor{ID: 1, Sort: UserSortCreatedAt, Descending: true}
+		}, true},
+		{"cursor of other sort", func(s *UserSearch) {
+			s.After = &UserSearchCursor{ID: 1, Sort: UserSortBalance, Descending: true}
+		}, false},
+		{"cursor of other order", func(s *UserSearch) { s.After = &UserSearchCursor{ID: 1, Sort: UserSortCreatedAt} }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			search := valid()
+			tt.modify(&search)
+			err := search.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Expected valid search, got %v", err)
+			}
+			var searchErr *InvalidUserSearchError
+			if !tt.valid && !errors.As(err, &searchErr) {
+				t.Errorf("Expected InvalidUserSearchError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestUserSearchCursor(t *testing.T) {
+	user := &UserSearchResult{
+		User:          User{ID: 7, Username: "alice", Balance: 150, CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 123456000, time.UTC)},
+		ReferralCount: 3,
+	}
+
+	tests := []struct {
+		sort  string
+		value string
+	}{
+		{UserSortCreatedAt, "2026-01-02T03:04:05.123456Z"},
+		{UserSortUsername, "alice"},
+		{UserSortBalance, "150"},
+		{UserSortReferrals, "3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			search := &UserSearch{Sort: tt.sort, Descending: true}
+			cursor, err := DecodeUserSearchCursor(search.CursorAfter(user).Encode())
+			if err != nil {
+				t.Fatalf("Failed to decode cursor: %v", err)
+			}
+			if cursor.ID != user.ID || cursor.Value != tt.value || cursor.Sort != tt.sort || !cursor.Descending {
+				t.Errorf("Unexpected cursor %+v", cursor)
+			}
+		})
+	}
+
+	invalid := []string{
+		"not base64!",
+		"bm90IGpzb24",
+		(&UserSearchCursor{ID: 1, Sort: UserSortBalance, Value: "abc"}).Encode(),
+		(&UserSearchCursor{ID: 1, Sort: UserSortCreatedAt, Value: "yesterday"}).Encode(),
+		(&UserSearchCursor{Sort: UserSortUsername, Value: "alice"}).Encode(),
+	}
+	for _, token := range invalid {
+		var searchErr *InvalidUserSearchError
+		if _, err := DecodeUserSearchCursor(token); !errors.As(err, &searchErr) {
+			t.Errorf("Expected InvalidUserSearchError for %q, got %v", token, err)
+		}
+	}
+}
+
+// readImport reads all rows of an import file, collecting row errors
+func readImport(t *testing.T, input, format string) ([]*ImportRow, []*InvalidImportRowError) {
+	t.Helper()
+	reader, err := NewImportReader(strings.NewReader(input), format)
+	if err != nil {
+		t.Fatalf("Failed to create import reader: %v", err)
+	}
+
+	var rows []*ImportRow
+	var rowErrors []*InvalidImportRowError
+	for {
+		row, err := reader.Next()
+		if err == io.EOF {
+			return rows, rowErrors
+		}
+		var rowErr *InvalidImportRowError
+		if errors.As(err, &rowErr) {
+			rowErrors = append(rowErrors, rowErr)
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexpected import error: %v", err)
+		}
+		rows = append(rows, row)
+	}
+}
+
+func TestImportReader(t *testing.T) {
+	tests := []struct {
+		name       string
+		format     string
+		input      string
+		rows       []ImportRow
+		errorLines []int
+	}{
+		{
+			name:   "csv",
+			format: ImportFormatCSV,
+			input:  "username,referrer_username,balance\nalice,,100\nbob,alice,\n\"carol\",bob,-5\ndave,dave,0\n,alice,1\neve,alice,ten\nfrank\n",
+			rows: []ImportRow{
+				{Line: 2, Username: "alice", Balance: 100},
+				{Line: 3, Username: "bob", ReferrerUsername: "alice"},
+				{Line: 8, Username: "frank"},
+			},
+			errorLines: []int{4, 5, 6, 7},
+		},
+		{
+			name:   "csv columns in any order",
+			format: ImportFormatCSV,
+			input:  "\ufeffBalance, Username\n5,alice\n",
+			rows:   []ImportRow{{Line: 2, Username: "alice", Balance: 5}},
+		},
+		{
+			name:   "ndjson",
+			format: ImportFormatNDJSON,
+			input:  "{\"username\":\"alice\",\"balance\":100}\n\n{\"username\":\"bob\",\"referrer_username\":\"alice\"}\nnot json\n{\"username\":\"carol\",\"email\":\"c@example.com\"}\n{\"username\":\"dave\",\"balance\":-1}\n",
+			rows: []ImportRow{
+				{Line: 1, Username: "alice", Balance: 100},
+				{Line: 3, Username: "bob", ReferrerUsername: "alice"},
+			},
+			errorLines: []int{4, 5, 6},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, rowErrors := readImport(t, tt.input, tt.format)
+			if len(rows) != len(tt.rows) {
+				t.Fatalf("Expected %d rows, got %d", len(tt.rows), len(rows))
+			}
+			for i, row := range rows {
+				if *row != tt.rows[i] {
+					t.Errorf("Row %d: expected %+v, got %+v", i, tt.rows[i], *row)
+				}
+			}
+			if len(rowErrors) != len(tt.errorLines) {
+				t.Fatalf("Expected %d row errors, got %d", len(tt.errorLines), len(rowErrors))
+			}
+			for i, rowErr := range rowErrors {
+				if rowErr.Line != tt.errorLines[i] {
+					t.Errorf("Row error %d: expected line %d, got %d (%v)", i, tt.errorLines[i], rowErr.Line, rowErr)
+				}
+			}
+		})
+	}
+}
+
+func TestNewImportReaderInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		input  string
+	}{
+		{"unknown format", "xml", "<users/>"},
+		{"empty csv", ImportFormatCSV, ""},
+		{"no username column", ImportFormatCSV, "name,balance\nalice,1\n"},
+		{"unknown column", ImportFormatCSV, "username,email\nalice,a@example.com\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var importErr *InvalidImportError
+			if _, err := NewImportReader(strings.NewReader(tt.input), tt.format); !errors.As(err, &importErr) {
+				t.Errorf("Expected InvalidImportError, got %v", err)
+			}
+		})
+	}
+}
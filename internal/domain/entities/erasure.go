@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// ErasedFields lists the personal data removed when a user is anonymised,
+// including moderators' free-text notes about the user
+var ErasedFields = []string{
+	"username", "display_name", "email", "avatar_url", "locale", "timezone",
+	"attributes", "username_history", "task_proofs", "review_notes",
+	"status_reason", "status_change_reasons", "data_exports",
+}
+
+// DeletionReceipt confirms that a deleted user was anonymised. Balances and
+// transactions are kept for the audit trail, linked only to the user ID
+type DeletionReceipt struct {
+	ID                     int64     `json:"id"`
+	UserID                 int64     `json:"user_id"`
+	RequestedBy            *int64    `json:"requested_by,omitempty"`
+	UsernameChangesDeleted int64     `json:"username_changes_deleted"`
+	ProofsScrubbed         int64     `json:"proofs_scrubbed"`
+	TransactionsRetained   int64     `json:"transactions_retained"`
+	RequestedAt            time.Time `json:"requested_at"`
+	ErasedAt               time.Time `json:"erased_at"`
+	ErasedFields           []string  `json:"erased_fields"`
+}
+
+// AccountDeletion is the state of a soft deleted user awaiting anonymisation
+type AccountDeletion struct {
+	UserID     int64     `json:"user_id"`
+	DeletedAt  time.Time `json:"deleted_at"`
+	EraseAfter time.Time `json:"erase_after"`
+}
+
+// AnonymizedUsername returns the username of an anonymised user. It breaks the
+// username policy, so nobody can register it
+func AnonymizedUsername(userID int64) string {
+	return fmt.Sprintf("deleted#%d", userID)
+}
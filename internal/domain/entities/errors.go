@@ -1,6 +1,10 @@
 package entities
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // InsufficientBalanceError represents an error when user doesn't have enough balance
 type InsufficientBalanceError struct {
@@ -30,6 +34,34 @@ func (e *UserNotFoundError) Error() string {
 	return fmt.Sprintf("user with id %d not found", e.ID)
 }
 
+// UserRestrictedError represents an error when a suspended or banned user tries to act
+type UserRestrictedError struct {
+	UserID int64
+	Until  *time.Time
+	Status string
+	Reason *string
+}
+
+func (e *UserRestrictedError) Error() string {
+	message := fmt.Sprintf("user %d is %s", e.UserID, e.Status)
+	if e.Until != nil {
+		message += " until " + e.Until.UTC().Format(time.RFC3339)
+	}
+	if e.Reason != nil {
+		message += ": " + *e.Reason
+	}
+	return message
+}
+
+// InvalidStatusChangeError represents an error when a user status change is not allowed
+type InvalidStatusChangeError struct {
+	Reason string
+}
+
+func (e *InvalidStatusChangeError) Error() string {
+	return fmt.Sprintf("invalid status change: %s", e.Reason)
+}
+
 // TaskNotFoundError represents an error when task is not found
 type TaskNotFoundError struct {
 	ID int64
@@ -56,3 +88,310 @@ type AlreadyHasReferrerError struct {
 func (e *AlreadyHasReferrerError) Error() string {
 	return fmt.Sprintf("user %d already has a referrer", e.UserID)
 }
+
+// CurrencyNotFoundError represents an error when currency is not found
+type CurrencyNotFoundError struct {
+	Code string
+}
+
+func (e *CurrencyNotFoundError) Error() string {
+	return fmt.Sprintf("currency %q not found", e.Code)
+}
+
+// InvalidLeaderboardWindowError represents an error when leaderboard window is unknown
+type InvalidLeaderboardWindowError struct {
+	Window string
+}
+
+func (e *InvalidLeaderboardWindowError) Error() string {
+	return fmt.Sprintf("invalid leaderboard window %q: expected daily, weekly, monthly or all_time", e.Window)
+}
+
+// InvalidRankingModeError represents an error when ranking mode is unknown
+type InvalidRankingModeError struct {
+	Mode string
+}
+
+func (e *InvalidRankingModeError) Error() string {
+	return fmt.Sprintf("invalid ranking mode %q: expected competition or dense", e.Mode)
+}
+
+// InsufficientFreezesError represents an error when user doesn't have enough streak freezes
+type InsufficientFreezesError struct {
+	UserID int64
+}
+
+func (e *InsufficientFreezesError) Error() string {
+	return fmt.Sprintf("insufficient streak freezes for user %d", e.UserID)
+}
+
+// TaskNotStartedError represents an error when task availability window hasn't started yet
+type TaskNotStartedError struct {
+	ID       int64
+	StartsAt time.Time
+}
+
+func (e *TaskNotStartedError) Error() string {
+	return fmt.Sprintf("task %d is not available until %s", e.ID, e.StartsAt.Format(time.RFC3339))
+}
+
+// TaskExpiredError represents an error when task availability window has ended
+type TaskExpiredError struct {
+	ID int64
+}
+
+func (e *TaskExpiredError) Error() string {
+	return fmt.Sprintf("task %d has expired", e.ID)
+}
+
+// TaskCapReachedError represents an error when task reached its total completions cap
+type TaskCapReachedError struct {
+	ID int64
+}
+
+func (e *TaskCapReachedError) Error() string {
+	return fmt.Sprintf("task %d has no completion slots left", e.ID)
+}
+
+// TaskUserLimitReachedError represents an error when user reached the task's per-user completions limit
+type TaskUserLimitReachedError struct {
+	UserID int64
+	TaskID int64
+	Limit  int
+}
+
+func (e *TaskUserLimitReachedError) Error() string {
+	return fmt.Sprintf("user %d reached the limit of %d completions of task %d", e.UserID, e.Limit, e.TaskID)
+}
+
+// TaskLockedError represents an error when task prerequisites are not completed yet
+type TaskLockedError struct {
+	TaskID  int64
+	Missing []int64
+}
+
+func (e *TaskLockedError) Error() string {
+	return fmt.Sprintf("task %d is locked: complete tasks %v first", e.TaskID, e.Missing)
+}
+
+// InvalidProofError represents an error when submitted task proof is invalid
+type InvalidProofError struct {
+	Reason string
+}
+
+func (e *InvalidProofError) Error() string {
+	return fmt.Sprintf("invalid proof: %s", e.Reason)
+}
+
+// SubmissionNotFoundError represents an error when task submission is not found
+type SubmissionNotFoundError struct {
+	ID int64
+}
+
+func (e *SubmissionNotFoundError) Error() string {
+	return fmt.Sprintf("submission with id %d not found", e.ID)
+}
+
+// SubmissionNotPendingError represents an error when reviewing an already reviewed submission
+type SubmissionNotPendingError struct {
+	ID     int64
+	Status string
+}
+
+func (e *SubmissionNotPendingError) Error() string {
+	return fmt.Sprintf("submission %d is already %s", e.ID, e.Status)
+}
+
+// PartnerNotFoundError represents an error when partner is not found or inactive
+type PartnerNotFoundError struct {
+	Code string
+}
+
+func (e *PartnerNotFoundError) Error() string {
+	return fmt.Sprintf("partner %q not found", e.Code)
+}
+
+// InvalidSignatureError represents an error when callback signature doesn't match its payload
+type InvalidSignatureError struct{}
+
+func (e *InvalidSignatureError) Error() string {
+	return "invalid signature"
+}
+
+// InvalidCallbackError represents an error when partner callback payload is invalid
+type InvalidCallbackError struct {
+	Reason string
+}
+
+func (e *InvalidCallbackError) Error() string {
+	return fmt.Sprintf("invalid callback: %s", e.Reason)
+}
+
+// CallbackReplayError represents an error when partner callback nonce was already used
+type CallbackReplayError struct {
+	Nonce string
+}
+
+func (e *CallbackReplayError) Error() string {
+	return fmt.Sprintf("nonce %q was already used", e.Nonce)
+}
+
+// TaskPartnerMismatchError represents an error when partner completes a task it doesn't verify
+type TaskPartnerMismatchError struct {
+	TaskCode    string
+	PartnerCode string
+}
+
+func (e *TaskPartnerMismatchError) Error() string {
+	return fmt.Sprintf("task %q is not verified by partner %q", e.TaskCode, e.PartnerCode)
+}
+
+// TaskNotTargetedError represents an error when task targeting rules exclude the user
+type TaskNotTargetedError struct {
+	TaskID int64
+	Reason string
+}
+
+func (e *TaskNotTargetedError) Error() string {
+	return fmt.Sprintf("task %d is not available to this user: %s", e.TaskID, e.Reason)
+}
+
+// SegmentNotFoundError represents an error when segment is not found
+type SegmentNotFoundError struct {
+	Code string
+}
+
+func (e *SegmentNotFoundError) Error() string {
+	return fmt.Sprintf("segment %q not found", e.Code)
+}
+
+// SegmentMemberNotFoundError represents an error when user is not a member of segment
+type SegmentMemberNotFoundError struct {
+	Code   string
+	UserID int64
+}
+
+func (e *SegmentMemberNotFoundError) Error() string {
+	return fmt.Sprintf("user with id %d is not a member of segment %q", e.UserID, e.Code)
+}
+
+// CampaignExistsError represents an error when a campaign code is already used
+type CampaignExistsError struct {
+	Code string
+}
+
+func (e *CampaignExistsError) Error() string {
+	return fmt.Sprintf("campaign %q already exists", e.Code)
+}
+
+// InvalidCampaignError represents an error when campaign settings are invalid
+type InvalidCampaignError struct {
+	Reason string
+}
+
+func (e *InvalidCampaignError) Error() string {
+	return fmt.Sprintf("invalid campaign: %s", e.Reason)
+}
+
+// InvalidProfileError represents an error when a profile update is invalid
+type InvalidProfileError struct {
+	Field  string
+	Reason string
+}
+
+func (e *InvalidProfileError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("invalid profile: %s", e.Reason)
+	}
+	return fmt.Sprintf("invalid profile: %s %s", e.Field, e.Reason)
+}
+
+// InvalidUsernameError represents an error when a username breaks the username policy
+type InvalidUsernameError struct {
+	Username   string
+	Violations []UsernameViolation
+}
+
+func (e *InvalidUsernameError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Message
+	}
+	return fmt.Sprintf("invalid username %q: %s", e.Username, strings.Join(messages, "; "))
+}
+
+// ProfileFieldTakenError represents an error when a username or email belongs to another user
+type ProfileFieldTakenError struct {
+	Field string
+	Value string
+}
+
+func (e *ProfileFieldTakenError) Error() string {
+	return fmt.Sprintf("%s %q is already taken", e.Field, e.Value)
+}
+
+// InvalidExportFormatError represents an error when a data export format is not supported
+type InvalidExportFormatError struct {
+	Format string
+}
+
+func (e *InvalidExportFormatError) Error() string {
+	return fmt.Sprintf("invalid export format %q: must be json or zip", e.Format)
+}
+
+// DataExportNotFoundError represents an error when a data export is not found
+type DataExportNotFoundError struct {
+	ID int64
+}
+
+func (e *DataExportNotFoundError) Error() string {
+	return fmt.Sprintf("data export %d not found", e.ID)
+}
+
+// DataExportNotReadyError represents an error when a data export can't be downloaded yet
+type DataExportNotReadyError struct {
+	ID     int64
+	Status string
+}
+
+func (e *DataExportNotReadyError) Error() string {
+	return fmt.Sprintf("data export %d is %s", e.ID, e.Status)
+}
+
+// DataExportExpiredError represents an error when a data export is no longer available
+type DataExportExpiredError struct {
+	ID int64
+}
+
+func (e *DataExportExpiredError) Error() string {
+	return fmt.Sprintf("data export %d has expired", e.ID)
+}
+
+// InvalidUserSearchError represents an error when user search parameters are invalid
+type InvalidUserSearchError struct {
+	Reason string
+}
+
+func (e *InvalidUserSearchError) Error() string {
+	return fmt.Sprintf("invalid user search: %s", e.Reason)
+}
+
+// InvalidImportError represents an error when an import file can't be read
+type InvalidImportError struct {
+	Reason string
+}
+
+func (e *InvalidImportError) Error() string {
+	return fmt.Sprintf("invalid import: %s", e.Reason)
+}
+
+// InvalidImportRowError represents an error when a row of an import file is invalid
+type InvalidImportRowError struct {
+	Line     int
+	Username string
+	Reason   string
+}
+
+func (e *InvalidImportRowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Reason)
+}
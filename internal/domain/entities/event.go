@@ -0,0 +1,25 @@
+package entities
+
+import "time"
+
+// EventType identifies a kind of live update
+type EventType string
+
+// Event types
+const (
+	// EventTransaction carries a new transaction of the user
+	EventTransaction EventType = "transaction"
+	// EventBalance carries the user's updated balance
+	EventBalance EventType = "balance"
+	// EventLeaderboard carries a user's updated leaderboard position, sent to everyone
+	EventLeaderboard EventType = "leaderboard"
+)
+
+// Event is a live update pushed to connected clients
+type Event struct {
+	// UserID is the recipient, or 0 for events broadcast to all users
+	UserID    int64       `json:"user_id,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+	Type      EventType   `json:"type"`
+}
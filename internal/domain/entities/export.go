@@ -0,0 +1,263 @@
+package entities
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Data export formats
+const (
+	// DataExportFormatJSON is a single JSON document
+	DataExportFormatJSON = "json"
+	// DataExportFormatZIP is a zip archive of CSV files and the JSON document
+	DataExportFormatZIP = "zip"
+)
+
+// Data export statuses
+const (
+	DataExportStatusPending    = "pending"
+	DataExportStatusProcessing = "processing"
+	DataExportStatusReady      = "ready"
+	DataExportStatusFailed     = "failed"
+	DataExportStatusExpired    = "expired"
+)
+
+// DataExport is a background job building an archive of a user's personal data
+type DataExport struct {
+	ID          int64      `json:"id"`
+	UserID      int64      `json:"user_id"`
+	SizeBytes   int64      `json:"size_bytes"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Format      string     `json:"format"`
+	Status      string     `json:"status"`
+	Error       *string    `json:"error,omitempty"`
+	Content     []byte     `json:"-"`
+}
+
+// IsExpired reports whether the export can no longer be downloaded at now
+func (e *DataExport) IsExpired(now time.Time) bool {
+	return e.Status == DataExportStatusExpired || (e.ExpiresAt != nil && !now.Before(*e.ExpiresAt))
+}
+
+// FileName returns the name of the downloaded archive
+func (e *DataExport) FileName() string {
+	return fmt.Sprintf("user-%d-export-%d.%s", e.UserID, e.ID, e.Format)
+}
+
+// ContentType returns the MIME type of the archive
+func (e *DataExport) ContentType() string {
+	if e.Format == DataExportFormatZIP {
+		return "application/zip"
+	}
+	return "application/json"
+}
+
+// ValidateExportFormat checks that format is json or zip
+func ValidateExportFormat(format string) error {
+	if format != DataExportFormatJSON && format != DataExportFormatZIP {
+		return &InvalidExportFormatError{Format: format}
+	}
+	return nil
+}
+
+// Referral is a user invited by another user
+type Referral struct {
+	UserID    int64     `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Username  string    `json:"username"`
+}
+
+// PersonalData is everything stored about a user
+type PersonalData struct {
+	ExportedAt      time.Time              `json:"exported_at"`
+	User            *User                  `json:"user"`
+	Balances        []*Balance             `json:"balances"`
+	Transactions    []*Transaction         `json:"transactions"`
+	Tasks           []*UserTaskWithDetails `json:"tasks"`
+	Referrals       []*Referral            `json:"referrals"`
+	UsernameHistory []*UsernameChange      `json:"username_history"`
+}
+
+// Archive encodes the personal data in the given export format
+func (d *PersonalData) Archive(format string) ([]byte, error) {
+	switch format {
+	case DataExportFormatJSON:
+		return json.MarshalIndent(d, "", "  ")
+	case DataExportFormatZIP:
+		return d.zip()
+	default:
+		return nil, &InvalidExportFormatError{Format: format}
+	}
+}
+
+// zip builds a zip archive with one CSV file per kind of data and the JSON document
+func (d *PersonalData) zip() ([]byte, error) {
+	document, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	files := []struct {
+		name    string
+		records [][]string
+	}{
+		{"user.csv", d.userRecords()},
+		{"balances.csv", d.balanceRecords()},
+		{"transactions.csv", d.transactionRecords()},
+		{"tasks.csv", d.taskRecords()},
+		{"referrals.csv", d.referralRecords()},
+		{"username_history.csv", d.usernameRecords()},
+	}
+
+	for _, file := range files {
+		w, err := archive.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		if err := csv.NewWriter(w).WriteAll(file.records); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+
+	w, err := archive.Create("data.json")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(document); err != nil {
+		return nil, err
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// userRecords returns the user as field, value rows
+func (d *PersonalData) userRecords() [][]string {
+	u := d.User
+	attributes, _ := json.Marshal(u.Attributes)
+	return [][]string{
+		{"field", "value"},
+		{"id", strconv.FormatInt(u.ID, 10)},
+		{"username", u.Username},
+		{"display_name", csvString(u.DisplayName)},
+		{"email", csvString(u.Email)},
+		{"avatar_url", csvString(u.AvatarURL)},
+		{"locale", csvString(u.Locale)},
+		{"timezone", csvString(u.Timezone)},
+		{"attributes", string(attributes)},
+		{"role", u.Role},
+		{"status", u.Status},
+		{"referrer_id", csvInt(u.ReferrerID)},
+		{"created_at", u.CreatedAt.Format(time.RFC3339)},
+		{"updated_at", csvTime(u.UpdatedAt)},
+	}
+}
+
+// balanceRecords returns balances as CSV rows with a header
+func (d *PersonalData) balanceRecords() [][]string {
+	records := [][]string{{"currency", "points", "lifetime_points", "updated_at"}}
+	for _, b := range d.Balances {
+		records = append(records, []string{
+			b.Currency,
+			strconv.FormatInt(b.Points, 10),
+			strconv.FormatInt(b.LifetimePoints, 10),
+			b.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+	return records
+}
+
+// transactionRecords returns transactions as CSV rows with a header
+func (d *PersonalData) transactionRecords() [][]string {
+	records := [][]string{{"id", "currency", "delta", "reason", "reference_type", "reference_id", "user_task_id", "campaign_id", "created_at"}}
+	for _, t := range d.Transactions {
+		records = append(records, []string{
+			strconv.FormatInt(t.ID, 10),
+			t.Currency,
+			strconv.FormatInt(t.Delta, 10),
+			t.Reason,
+			csvString(t.ReferenceType),
+			csvInt(t.ReferenceID),
+			csvInt(t.UserTaskID),
+			csvInt(t.CampaignID),
+			t.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return records
+}
+
+// taskRecords returns task completions as CSV rows with a header
+func (d *PersonalData) taskRecords() [][]string {
+	records := [][]string{{"id", "task_id", "task_code", "task_title", "task_version", "status", "reward_points", "completed_at", "proof_url", "proof_text", "review_note", "reviewed_at"}}
+	for _, t := range d.Tasks {
+		records = append(records, []string{
+			strconv.FormatInt(t.ID, 10),
+			strconv.FormatInt(t.TaskID, 10),
+			t.TaskCode,
+			t.TaskTitle,
+			strconv.Itoa(t.TaskVersion),
+			t.Status,
+			strconv.FormatInt(t.RewardPoints, 10),
+			t.CompletedAt.Format(time.RFC3339),
+			csvString(t.ProofURL),
+			csvString(t.ProofText),
+			csvString(t.ReviewNote),
+			csvTime(t.ReviewedAt),
+		})
+	}
+	return records
+}
+
+// referralRecords returns invited users as CSV rows with a header
+func (d *PersonalData) referralRecords() [][]string {
+	records := [][]string{{"user_id", "username", "created_at"}}
+	for _, r := range d.Referrals {
+		records = append(records, []string{strconv.FormatInt(r.UserID, 10), r.Username, r.CreatedAt.Format(time.RFC3339)})
+	}
+	return records
+}
+
+// usernameRecords returns previous usernames as CSV rows with a header
+func (d *PersonalData) usernameRecords() [][]string {
+	records := [][]string{{"username", "changed_at"}}
+	for _, c := range d.UsernameHistory {
+		records = append(records, []string{c.Username, c.ChangedAt.Format(time.RFC3339)})
+	}
+	return records
+}
+
+// csvString formats an optional string, empty if unset
+func csvString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// csvInt formats an optional integer, empty if unset
+func csvInt(i *int64) string {
+	if i == nil {
+		return ""
+	}
+	return strconv.FormatInt(*i, 10)
+}
+
+// csvTime formats an optional time as RFC 3339, empty if unset
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
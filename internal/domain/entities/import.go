@@ -0,0 +1,194 @@
+package entities
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// User import formats
+const (
+	// ImportFormatCSV is a CSV file with a header naming the columns
+	ImportFormatCSV = "csv"
+	// ImportFormatNDJSON is one JSON object per line
+	ImportFormatNDJSON = "ndjson"
+)
+
+// ImportReferenceType marks opening-balance transactions of imported users
+const ImportReferenceType = "import"
+
+// maxImportLineSize limits the size of an NDJSON line
+const maxImportLineSize = 64 << 10
+
+// ImportRow is a user to import. Referrers are matched by normalized username
+// and must exist or come earlier in the file
+type ImportRow struct {
+	Line             int    `json:"-"`
+	Balance          int64  `json:"balance"`
+	Username         string `json:"username"`
+	ReferrerUsername string `json:"referrer_username"`
+}
+
+// ImportRowError is a row that was not imported and why
+type ImportRowError struct {
+	Line     int    `json:"line"`
+	Username string `json:"username,omitempty"`
+	Error    string `json:"error"`
+}
+
+// ImportReport summarises an import, failed rows don't stop the rest of the file
+type ImportReport struct {
+	Total    int              `json:"total"`
+	Imported int              `json:"imported"`
+	Failed   int              `json:"failed"`
+	Currency string           `json:"currency"`
+	Errors   []ImportRowError `json:"errors"`
+}
+
+// AddError records a row that was not imported
+func (r *ImportReport) AddError(line int, username, message string) {
+	r.Failed++
+	r.Errors = append(r.Errors, ImportRowError{Line: line, Username: username, Error: message})
+}
+
+// ImportReader reads users to import from CSV or NDJSON
+type ImportReader struct {
+	csv     *csv.Reader
+	lines   *bufio.Scanner
+	columns map[string]int
+	line    int
+}
+
+// NewImportReader creates a reader of the format, CSV input must start with a
+// header with a username column and optional referrer_username and balance columns
+func NewImportReader(r io.Reader, format string) (*ImportReader, error) {
+	switch format {
+	case ImportFormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+
+		header, err := reader.Read()
+		if err != nil {
+			return nil, &InvalidImportError{Reason: fmt.Sprintf("failed to read CSV header: %v", err)}
+		}
+		columns := make(map[string]int)
+		for i, name := range header {
+			name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+			switch name {
+			case "username", "referrer_username", "balance":
+				columns[name] = i
+			default:
+				return nil, &InvalidImportError{Reason: fmt.Sprintf("unknown CSV column %q", name)}
+			}
+		}
+		if _, ok := columns["username"]; !ok {
+			return nil, &InvalidImportError{Reason: "CSV header has no username column"}
+		}
+		return &ImportReader{csv: reader, columns: columns}, nil
+
+	case ImportFormatNDJSON:
+		lines := bufio.NewScanner(r)
+		lines.Buffer(make([]byte, 0, 4096), maxImportLineSize)
+		return &ImportReader{lines: lines}, nil
+
+	default:
+		return nil, &InvalidImportError{Reason: fmt.Sprintf("unknown format %q: must be csv or ndjson", format)}
+	}
+}
+
+// Next returns the next row or io.EOF. A malformed row is reported as
+// InvalidImportRowError and reading can go on, other errors end the file
+func (r *ImportReader) Next() (*ImportRow, error) {
+	if r.csv != nil {
+		return r.nextCSV()
+	}
+	return r.nextNDJSON()
+}
+
+// nextCSV reads the next CSV record
+func (r *ImportReader) nextCSV() (*ImportRow, error) {
+	record, err := r.csv.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return nil, &InvalidImportRowError{Line: parseErr.Line, Reason: parseErr.Err.Error()}
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.line, _ = r.csv.FieldPos(0)
+
+	field := func(name string) string {
+		if i, ok := r.columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	row := &ImportRow{Line: r.line, Username: field("username"), ReferrerUsername: field("referrer_username")}
+	if balance := field("balance"); balance != "" {
+		if row.Balance, err = strconv.ParseInt(balance, 10, 64); err != nil {
+			return nil, &InvalidImportRowError{Line: r.line, Username: row.Username, Reason: "balance must be an integer"}
+		}
+	}
+	if err := row.validate(); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+// nextNDJSON reads the next non-empty NDJSON line
+func (r *ImportReader) nextNDJSON() (*ImportRow, error) {
+	for r.lines.Scan() {
+		r.line++
+		line := bytes.TrimSpace(r.lines.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.DisallowUnknownFields()
+		row := &ImportRow{}
+		if err := decoder.Decode(row); err != nil {
+			return nil, &InvalidImportRowError{Line: r.line, Reason: fmt.Sprintf("invalid JSON: %v", err)}
+		}
+		row.Line = r.line
+		row.Username = strings.TrimSpace(row.Username)
+		row.ReferrerUsername = strings.TrimSpace(row.ReferrerUsername)
+		if err := row.validate(); err != nil {
+			return nil, err
+		}
+		return row, nil
+	}
+
+	if err := r.lines.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, &InvalidImportError{Reason: fmt.Sprintf("line %d is longer than %d bytes", r.line+1, maxImportLineSize)}
+		}
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// validate checks the row fields that don't depend on the username policy
+func (row *ImportRow) validate() error {
+	switch {
+	case row.Username == "":
+		return &InvalidImportRowError{Line: row.Line, Reason: "username is required"}
+	case row.Balance < 0:
+		return &InvalidImportRowError{Line: row.Line, Username: row.Username, Reason: "balance can't be negative"}
+	case row.ReferrerUsername != "" && NormalizeUsername(row.ReferrerUsername) == NormalizeUsername(row.Username):
+		return &InvalidImportRowError{Line: row.Line, Username: row.Username, Reason: "user can't be their own referrer"}
+	}
+	return nil
+}
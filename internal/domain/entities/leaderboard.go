@@ -0,0 +1,79 @@
+package entities
+
+import "time"
+
+// LeaderboardWindow is a period over which earned points are ranked
+type LeaderboardWindow string
+
+// Leaderboard windows
+const (
+	// LeaderboardWindowDaily ranks points earned since the start of the current day
+	LeaderboardWindowDaily LeaderboardWindow = "daily"
+	// LeaderboardWindowWeekly ranks points earned since the start of the current week (Monday)
+	LeaderboardWindowWeekly LeaderboardWindow = "weekly"
+	// LeaderboardWindowMonthly ranks points earned since the start of the current month
+	LeaderboardWindowMonthly LeaderboardWindow = "monthly"
+	// LeaderboardWindowAllTime ranks points earned over all time
+	LeaderboardWindowAllTime LeaderboardWindow = "all_time"
+)
+
+// RankingMode defines how tied scores are ranked
+type RankingMode string
+
+// Ranking modes
+const (
+	// RankingCompetition gives tied users the same rank and skips the following ranks (1, 2, 2, 4)
+	RankingCompetition RankingMode = "competition"
+	// RankingDense gives tied users the same rank without gaps (1, 2, 2, 3)
+	RankingDense RankingMode = "dense"
+)
+
+// ParseRankingMode validates a ranking mode name, defaulting to competition ranking
+func ParseRankingMode(s string) (RankingMode, error) {
+	switch m := RankingMode(s); m {
+	case "":
+		return RankingCompetition, nil
+	case RankingCompetition, RankingDense:
+		return m, nil
+	}
+	return "", &InvalidRankingModeError{Mode: s}
+}
+
+// UserRank represents a user's position on the leaderboard
+type UserRank struct {
+	UserID     int64               `json:"user_id"`
+	Points     int64               `json:"points"`
+	Rank       int64               `json:"rank"`
+	Total      int64               `json:"total"`
+	Percentile float64             `json:"percentile"`
+	Currency   string              `json:"currency"`
+	Neighbours []*LeaderboardEntry `json:"neighbours,omitempty"`
+}
+
+// ParseLeaderboardWindow validates a leaderboard window name
+func ParseLeaderboardWindow(s string) (LeaderboardWindow, error) {
+	switch w := LeaderboardWindow(s); w {
+	case LeaderboardWindowDaily, LeaderboardWindowWeekly, LeaderboardWindowMonthly, LeaderboardWindowAllTime:
+		return w, nil
+	}
+	return "", &InvalidLeaderboardWindowError{Window: s}
+}
+
+// Start returns the beginning of the window containing now in the given location;
+// ok is false for the all-time window which has no beginning
+func (w LeaderboardWindow) Start(now time.Time, loc *time.Location) (start time.Time, ok bool) {
+	now = now.In(loc)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	switch w {
+	case LeaderboardWindowDaily:
+		return day, true
+	case LeaderboardWindowWeekly:
+		// Weeks start on Monday
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset), true
+	case LeaderboardWindowMonthly:
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc), true
+	}
+	return time.Time{}, false
+}
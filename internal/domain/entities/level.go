@@ -0,0 +1,112 @@
+package entities
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Level represents a tier reached once lifetime points pass a threshold
+type Level struct {
+	MinPoints int64  `json:"min_points"`
+	Number    int    `json:"level"`
+	Name      string `json:"name"`
+}
+
+// LevelProgress represents the current level of a user and progress to the next one
+type LevelProgress struct {
+	Level
+	LifetimePoints    int64   `json:"lifetime_points"`
+	PointsToNextLevel int64   `json:"points_to_next_level"`
+	Progress          float64 `json:"progress"`
+	NextLevel         *Level  `json:"next_level,omitempty"`
+}
+
+// LevelUp records a user crossing a level threshold
+type LevelUp struct {
+	ID             int64     `json:"id"`
+	UserID         int64     `json:"user_id"`
+	LifetimePoints int64     `json:"lifetime_points"`
+	CreatedAt      time.Time `json:"created_at"`
+	Level          int       `json:"level"`
+	Name           string    `json:"name"`
+}
+
+// Levels is a list of levels ordered by threshold
+type Levels []Level
+
+// ParseLevels parses levels from "Name:MinPoints" pairs separated by commas,
+// e.g. "Bronze:0,Silver:500,Gold:2000"
+func ParseLevels(spec string) (Levels, error) {
+	var levels Levels
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, pointsStr, ok := strings.Cut(part, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid level %q: expected Name:MinPoints", part)
+		}
+
+		minPoints, err := strconv.ParseInt(strings.TrimSpace(pointsStr), 10, 64)
+		if err != nil || minPoints < 0 {
+			return nil, fmt.Errorf("invalid threshold for level %q", name)
+		}
+
+		levels = append(levels, Level{Name: name, MinPoints: minPoints})
+	}
+
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("at least one level is required")
+	}
+
+	sort.SliceStable(levels, func(i, j int) bool {
+		return levels[i].MinPoints < levels[j].MinPoints
+	})
+	for i := range levels {
+		if i > 0 && levels[i].MinPoints == levels[i-1].MinPoints {
+			return nil, fmt.Errorf("levels %q and %q share threshold %d", levels[i-1].Name, levels[i].Name, levels[i].MinPoints)
+		}
+		levels[i].Number = i + 1
+	}
+
+	return levels, nil
+}
+
+// For returns the level reached with the given lifetime points
+func (l Levels) For(lifetimePoints int64) Level {
+	var current Level
+	for _, level := range l {
+		if lifetimePoints < level.MinPoints {
+			break
+		}
+		current = level
+	}
+	return current
+}
+
+// Progress returns the level reached with the given lifetime points and progress to the next level
+func (l Levels) Progress(lifetimePoints int64) LevelProgress {
+	progress := LevelProgress{
+		Level:          l.For(lifetimePoints),
+		LifetimePoints: lifetimePoints,
+		Progress:       1,
+	}
+
+	if progress.Number < len(l) {
+		next := l[progress.Number]
+		progress.NextLevel = &next
+		progress.PointsToNextLevel = next.MinPoints - lifetimePoints
+		span := next.MinPoints - progress.MinPoints
+		if span > 0 {
+			progress.Progress = float64(lifetimePoints-progress.MinPoints) / float64(span)
+		}
+	}
+
+	return progress
+}
@@ -0,0 +1,110 @@
+package entities
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TaskCategory groups tasks into a section of the task list
+type TaskCategory struct {
+	ID        int64  `json:"id"`
+	SortOrder int    `json:"sort_order"`
+	Code      string `json:"code"`
+	Title     string `json:"title"`
+}
+
+// TaskTranslation is a task title and description in a locale
+type TaskTranslation struct {
+	TaskID      int64  `json:"task_id"`
+	Locale      string `json:"locale"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// ParseAcceptLanguage returns locales from an Accept-Language header ordered by preference.
+// Locales are lowercased and a region-specific locale ("pt-BR") is followed by its
+// base language ("pt"); wildcards and locales with zero quality are skipped
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var items []weighted
+	for _, part := range splitPairs(header) {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		items = append(items, weighted{locale: tag, q: q})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].q > items[j].q
+	})
+
+	seen := make(map[string]bool)
+	var locales []string
+	add := func(locale string) {
+		if !seen[locale] {
+			seen[locale] = true
+			locales = append(locales, locale)
+		}
+	}
+	for _, item := range items {
+		add(item.locale)
+		if base, _, ok := strings.Cut(item.locale, "-"); ok {
+			add(base)
+		}
+	}
+	return locales
+}
+
+// Localize replaces the task title and description with the translation in the most
+// preferred locale. Base title and description are in defaultLocale, which is used
+// when no preferred locale has a translation or defaultLocale is preferred over them
+func (t *Task) Localize(translations []*TaskTranslation, locales []string, defaultLocale string) {
+	t.Locale = defaultLocale
+	if tr := findTranslation(translations, t.ID, locales, defaultLocale); tr != nil {
+		t.Title = tr.Title
+		t.Description = tr.Description
+		t.Locale = tr.Locale
+	}
+}
+
+// Localize replaces the task title with the translation in the most preferred locale
+func (u *UserTaskWithDetails) Localize(translations []*TaskTranslation, locales []string, defaultLocale string) {
+	if tr := findTranslation(translations, u.TaskID, locales, defaultLocale); tr != nil {
+		u.TaskTitle = tr.Title
+	}
+}
+
+// findTranslation returns the task translation in the most preferred locale or nil
+// if the base text in defaultLocale should be used
+func findTranslation(translations []*TaskTranslation, taskID int64, locales []string, defaultLocale string) *TaskTranslation {
+	for _, locale := range locales {
+		if locale == defaultLocale {
+			return nil
+		}
+		for _, tr := range translations {
+			if tr.TaskID == taskID && tr.Locale == locale {
+				return tr
+			}
+		}
+	}
+	return nil
+}
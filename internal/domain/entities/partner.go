@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// MaxNonceLength is the maximum length of a partner callback nonce
+const MaxNonceLength = 128
+
+// Partner is an external system that completes tasks on behalf of users
+type Partner struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Code      string    `json:"code"`
+	Title     string    `json:"title"`
+	Secret    string    `json:"-"`
+	IsActive  bool      `json:"is_active"`
+}
+
+// PartnerCallback is a signed notification that a user completed a task in a partner system
+type PartnerCallback struct {
+	UserID    int64  `json:"user_id"`
+	Timestamp int64  `json:"timestamp"`
+	TaskCode  string `json:"task_code"`
+	Nonce     string `json:"nonce"`
+}
+
+// Validate checks callback fields and that its timestamp (unix seconds) is within maxSkew of now
+func (c *PartnerCallback) Validate(now time.Time, maxSkew time.Duration) error {
+	if c.UserID <= 0 {
+		return &InvalidCallbackError{Reason: "user_id must be positive"}
+	}
+	if strings.TrimSpace(c.TaskCode) == "" {
+		return &InvalidCallbackError{Reason: "task_code is required"}
+	}
+	if c.Nonce == "" || len(c.Nonce) > MaxNonceLength {
+		return &InvalidCallbackError{Reason: "nonce is required and must be at most 128 characters"}
+	}
+
+	skew := now.Sub(time.Unix(c.Timestamp, 0))
+	if skew > maxSkew || skew < -maxSkew {
+		return &InvalidCallbackError{Reason: "timestamp is outside the allowed window"}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package entities
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Profile limits
+const (
+	// MaxDisplayNameLength is the maximum length of a display name in characters
+	MaxDisplayNameLength = 100
+	// MaxAttributesSize is the maximum size of JSON encoded profile attributes in bytes
+	MaxAttributesSize = 16 * 1024
+)
+
+var localePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// Profile is the user-editable part of a user
+type Profile struct {
+	DisplayName *string        `json:"display_name"`
+	Email       *string        `json:"email"`
+	AvatarURL   *string        `json:"avatar_url"`
+	Locale      *string        `json:"locale"`
+	Timezone    *string        `json:"timezone"`
+	Attributes  map[string]any `json:"attributes"`
+}
+
+// UsernameChange records a previous username of a user
+type UsernameChange struct {
+	UserID    int64     `json:"user_id"`
+	ChangedAt time.Time `json:"changed_at"`
+	Username  string    `json:"username"`
+}
+
+// ProfilePatch is a JSON merge patch (RFC 7396) of a user's username and profile
+type ProfilePatch map[string]json.RawMessage
+
+// ParseProfilePatch parses a JSON merge patch document, which must be an object
+func ParseProfilePatch(data []byte) (ProfilePatch, error) {
+	var patch ProfilePatch
+	if err := json.Unmarshal(data, &patch); err != nil || patch == nil {
+		return nil, &InvalidProfileError{Reason: "patch must be a JSON object"}
+	}
+	return patch, nil
+}
+
+// Apply applies the patch to user and validates the profile, a changed username is
+// left for the UsernamePolicy. A null value removes a profile field, attributes are
+// merged recursively
+func (p ProfilePatch) Apply(user *User) error {
+	for field, raw := range p {
+		isNull := bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+
+		switch field {
+		case "username":
+			var username string
+			if isNull || json.Unmarshal(raw, &username) != nil {
+				return &InvalidProfileError{Field: field, Reason: "must be a string"}
+			}
+			user.Username = strings.TrimSpace(username)
+		case "display_name":
+			if err := patchString(field, raw, isNull, &user.DisplayName); err != nil {
+				return err
+			}
+		case "email":
+			if err := patchString(field, raw, isNull, &user.Email); err != nil {
+				return err
+			}
+		case "avatar_url":
+			if err := patchString(field, raw, isNull, &user.AvatarURL); err != nil {
+				return err
+			}
+		case "locale":
+			if err := patchString(field, raw, isNull, &user.Locale); err != nil {
+				return err
+			}
+		case "timezone":
+			if err := patchString(field, raw, isNull, &user.Timezone); err != nil {
+				return err
+			}
+		case "attributes":
+			if isNull {
+				user.Attributes = map[string]any{}
+				continue
+			}
+			var attributes map[string]any
+			if err := json.Unmarshal(raw, &attributes); err != nil || attributes == nil {
+				return &InvalidProfileError{Field: field, Reason: "must be an object"}
+			}
+			user.Attributes = MergePatch(user.Attributes, attributes)
+		default:
+			return &InvalidProfileError{Field: field, Reason: "cannot be changed"}
+		}
+	}
+
+	return user.Profile.Validate()
+}
+
+// patchString sets a nullable string field from a patch value, null or an empty string clear it
+func patchString(field string, raw json.RawMessage, isNull bool, target **string) error {
+	if isNull {
+		*target = nil
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return &InvalidProfileError{Field: field, Reason: "must be a string or null"}
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		*target = nil
+		return nil
+	}
+	*target = &value
+	return nil
+}
+
+// Validate checks profile fields, email is normalized to lower case
+func (p *Profile) Validate() error {
+	if p.DisplayName != nil && utf8.RuneCountInString(*p.DisplayName) > MaxDisplayNameLength {
+		return &InvalidProfileError{Field: "display_name", Reason: "is too long"}
+	}
+
+	if p.Email != nil {
+		addr, err := mail.ParseAddress(*p.Email)
+		if err != nil || addr.Address != *p.Email {
+			return &InvalidProfileError{Field: "email", Reason: "is not a valid address"}
+		}
+		email := strings.ToLower(addr.Address)
+		p.Email = &email
+	}
+
+	if p.AvatarURL != nil {
+		u, err := url.Parse(*p.AvatarURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return &InvalidProfileError{Field: "avatar_url", Reason: "must be an http(s) URL"}
+		}
+	}
+
+	if p.Locale != nil && !localePattern.MatchString(*p.Locale) {
+		return &InvalidProfileError{Field: "locale", Reason: "must be a language tag like \"en\" or \"pt-BR\""}
+	}
+
+	if p.Timezone != nil {
+		if _, err := time.LoadLocation(*p.Timezone); err != nil || *p.Timezone == "Local" {
+			return &InvalidProfileError{Field: "timezone", Reason: "must be an IANA time zone"}
+		}
+	}
+
+	if p.Attributes == nil {
+		p.Attributes = map[string]any{}
+	}
+	data, err := json.Marshal(p.Attributes)
+	if err != nil || len(data) > MaxAttributesSize {
+		return &InvalidProfileError{Field: "attributes", Reason: "are too large"}
+	}
+
+	return nil
+}
+
+// MergePatch applies a JSON merge patch object to target (RFC 7396): null values remove
+// keys, nested objects are merged and other values replace existing ones
+func MergePatch(target, patch map[string]any) map[string]any {
+	merged := make(map[string]any, len(target)+len(patch))
+	for key, value := range target {
+		merged[key] = value
+	}
+
+	for key, value := range patch {
+		switch v := value.(type) {
+		case nil:
+			delete(merged, key)
+		case map[string]any:
+			current, _ := merged[key].(map[string]any)
+			merged[key] = MergePatch(current, v)
+		default:
+			merged[key] = v
+		}
+	}
+
+	return merged
+}
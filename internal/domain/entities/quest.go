@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+// QuestChain groups tasks into a quest with a bonus for completing all of them
+type QuestChain struct {
+	ID          int64     `json:"id"`
+	BonusPoints int64     `json:"bonus_points"`
+	CreatedAt   time.Time `json:"created_at"`
+	Code        string    `json:"code"`
+	Title       string    `json:"title"`
+	TaskIDs     []int64   `json:"task_ids"`
+	IsActive    bool      `json:"is_active"`
+}
+
+// UserQuestChain represents a user's progress in a quest chain
+type UserQuestChain struct {
+	QuestChain
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+	CompletedTaskIDs []int64    `json:"completed_task_ids"`
+}
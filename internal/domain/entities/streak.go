@@ -0,0 +1,144 @@
+package entities
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Streak tracks consecutive periods in which a user completed a recurring task
+type Streak struct {
+	UserID          int64     `json:"user_id"`
+	TaskID          int64     `json:"task_id"`
+	LastPeriodStart time.Time `json:"last_period_start"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	TaskCode        string    `json:"task_code,omitempty"`
+	TaskTitle       string    `json:"task_title,omitempty"`
+	Current         int       `json:"current"`
+	Longest         int       `json:"longest"`
+}
+
+// StreakSummary represents all streaks of a user and available streak freezes
+type StreakSummary struct {
+	Streaks          []*Streak `json:"streaks"`
+	FreezesAvailable int       `json:"freezes_available"`
+}
+
+// Advance records a completion in the period starting at periodStart and returns
+// how many streak freezes were used to bridge missed periods
+func (s *Streak) Advance(periodStart time.Time, periodLength time.Duration, freezes int) int {
+	used := 0
+
+	switch {
+	case s.Current == 0 || s.LastPeriodStart.IsZero():
+		s.Current = 1
+	default:
+		// Round to absorb daylight saving shifts of day and week lengths
+		gap := int(math.Round(float64(periodStart.Sub(s.LastPeriodStart)) / float64(periodLength)))
+		switch {
+		case gap <= 0:
+			// Same period, nothing to advance
+			return 0
+		case gap == 1:
+			s.Current++
+		case gap-1 <= freezes:
+			used = gap - 1
+			s.Current++
+		default:
+			s.Current = 1
+		}
+	}
+
+	if s.Current > s.Longest {
+		s.Longest = s.Current
+	}
+	s.LastPeriodStart = periodStart
+	return used
+}
+
+// StreakMilestone is a streak length with a reward multiplier or a one-time bonus
+type StreakMilestone struct {
+	Streak     int     `json:"streak"`
+	Multiplier float64 `json:"multiplier,omitempty"`
+	Bonus      int64   `json:"bonus,omitempty"`
+}
+
+// StreakRules defines reward multipliers and bonuses at streak milestones
+type StreakRules struct {
+	Multipliers []StreakMilestone
+	Bonuses     []StreakMilestone
+}
+
+// ParseStreakRules parses multipliers ("3:1.5,7:2" - from streak 3 rewards are
+// multiplied by 1.5) and bonuses ("7:100" - 100 points when a streak reaches 7)
+func ParseStreakRules(multipliers, bonuses string) (StreakRules, error) {
+	var rules StreakRules
+
+	for _, part := range splitPairs(multipliers) {
+		streakStr, valueStr, _ := strings.Cut(part, ":")
+		streak, err := strconv.Atoi(strings.TrimSpace(streakStr))
+		if err != nil || streak <= 0 {
+			return StreakRules{}, fmt.Errorf("invalid streak in multiplier %q", part)
+		}
+		multiplier, err := strconv.ParseFloat(strings.TrimSpace(valueStr), 64)
+		if err != nil || multiplier < 1 {
+			return StreakRules{}, fmt.Errorf("invalid multiplier %q: must be at least 1", part)
+		}
+		rules.Multipliers = append(rules.Multipliers, StreakMilestone{Streak: streak, Multiplier: multiplier})
+	}
+
+	for _, part := range splitPairs(bonuses) {
+		streakStr, valueStr, _ := strings.Cut(part, ":")
+		streak, err := strconv.Atoi(strings.TrimSpace(streakStr))
+		if err != nil || streak <= 0 {
+			return StreakRules{}, fmt.Errorf("invalid streak in bonus %q", part)
+		}
+		bonus, err := strconv.ParseInt(strings.TrimSpace(valueStr), 10, 64)
+		if err != nil || bonus <= 0 {
+			return StreakRules{}, fmt.Errorf("invalid bonus %q: must be positive", part)
+		}
+		rules.Bonuses = append(rules.Bonuses, StreakMilestone{Streak: streak, Bonus: bonus})
+	}
+
+	sort.Slice(rules.Multipliers, func(i, j int) bool {
+		return rules.Multipliers[i].Streak < rules.Multipliers[j].Streak
+	})
+
+	return rules, nil
+}
+
+// Multiplier returns the reward multiplier for the streak length
+func (r StreakRules) Multiplier(streak int) float64 {
+	multiplier := 1.0
+	for _, m := range r.Multipliers {
+		if streak >= m.Streak {
+			multiplier = m.Multiplier
+		}
+	}
+	return multiplier
+}
+
+// Bonus returns the one-time bonus for reaching exactly the streak length
+func (r StreakRules) Bonus(streak int) int64 {
+	var bonus int64
+	for _, b := range r.Bonuses {
+		if b.Streak == streak {
+			bonus += b.Bonus
+		}
+	}
+	return bonus
+}
+
+// splitPairs splits a comma separated list skipping empty items
+func splitPairs(s string) []string {
+	var parts []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
@@ -1,30 +1,261 @@
 package entities
 
-import "time"
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MaxProofTextLength is the maximum length of a text proof
+const MaxProofTextLength = 2000
+
+// Task recurrence rules
+const (
+	// TaskRecurrenceOnce tasks can be completed only once
+	TaskRecurrenceOnce = "once"
+	// TaskRecurrenceDaily tasks can be completed once per day
+	TaskRecurrenceDaily = "daily"
+	// TaskRecurrenceWeekly tasks can be completed once per week (starting Monday)
+	TaskRecurrenceWeekly = "weekly"
+	// TaskRecurrenceHourly tasks can be completed once every RecurrenceHours hours
+	TaskRecurrenceHourly = "hourly"
+)
+
+// Task verification modes
+const (
+	// TaskVerificationAuto tasks are completed immediately
+	TaskVerificationAuto = "auto"
+	// TaskVerificationManual tasks are completed after a moderator approves the proof
+	TaskVerificationManual = "manual"
+	// TaskVerificationCallback tasks are completed after an external partner confirms them
+	TaskVerificationCallback = "callback"
+)
+
+// User task statuses
+const (
+	// UserTaskStatusPending submissions await verification
+	UserTaskStatusPending = "pending"
+	// UserTaskStatusCompleted submissions are verified and rewarded
+	UserTaskStatusCompleted = "completed"
+	// UserTaskStatusRejected submissions were rejected and can be resubmitted
+	UserTaskStatusRejected = "rejected"
+)
 
 // Task represents a task that users can complete
 type Task struct {
-	ID           int64     `json:"id"`
+	ID              int64         `json:"id"`
+	RewardPoints    int64         `json:"reward_points"`
+	Completions     int64         `json:"completions"`
+	MaxCompletions  *int64        `json:"max_completions,omitempty"`
+	RemainingSlots  *int64        `json:"remaining_slots,omitempty"`
+	ChainID         *int64        `json:"chain_id,omitempty"`
+	PartnerID       *int64        `json:"partner_id,omitempty"`
+	CategoryID      *int64        `json:"-"`
+	Category        *TaskCategory `json:"category,omitempty"`
+	CreatedAt       time.Time     `json:"created_at"`
+	StartsAt        *time.Time    `json:"starts_at,omitempty"`
+	EndsAt          *time.Time    `json:"ends_at,omitempty"`
+	Code            string        `json:"code"`
+	Title           string        `json:"title"`
+	Description     string        `json:"description"`
+	IconURL         *string       `json:"icon_url,omitempty"`
+	SortOrder       int           `json:"sort_order"`
+	Version         int           `json:"version"`
+	Tags            []string      `json:"tags"`
+	Locale          string        `json:"locale,omitempty"`
+	Recurrence      string        `json:"recurrence"`
+	RecurrenceHours *int          `json:"recurrence_hours,omitempty"`
+	MaxPerUser      *int          `json:"max_per_user,omitempty"`
+	Verification    string        `json:"verification"`
+	ExtraRewards    []TaskReward  `json:"extra_rewards,omitempty"`
+	Prerequisites   []int64       `json:"prerequisites,omitempty"`
+	Targeting       TaskTargeting `json:"targeting"`
+	IsActive        bool          `json:"is_active"`
+}
+
+// TaskVersion is a past or current title and reward of a task
+type TaskVersion struct {
+	TaskID       int64     `json:"task_id"`
 	RewardPoints int64     `json:"reward_points"`
 	CreatedAt    time.Time `json:"created_at"`
-	Code         string    `json:"code"`
+	Version      int       `json:"version"`
 	Title        string    `json:"title"`
-	IsActive     bool      `json:"is_active"`
 }
 
-// UserTask represents a completed task by a user
-type UserTask struct {
-	UserID      int64     `json:"user_id"`
-	TaskID      int64     `json:"task_id"`
-	CompletedAt time.Time `json:"completed_at"`
+// TaskReward represents a task reward in a non-default currency
+type TaskReward struct {
+	TaskID   int64  `json:"-"`
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
 }
 
-// UserTaskWithDetails represents a completed task with task details
-type UserTaskWithDetails struct {
+// Rewards returns all rewards of the task, starting with the default currency
+func (t *Task) Rewards() []TaskReward {
+	rewards := make([]TaskReward, 0, len(t.ExtraRewards)+1)
+	if t.RewardPoints > 0 {
+		rewards = append(rewards, TaskReward{TaskID: t.ID, Amount: t.RewardPoints, Currency: DefaultCurrency})
+	}
+	return append(rewards, t.ExtraRewards...)
+}
+
+// IsRecurring reports whether the task can be completed again in later periods
+func (t *Task) IsRecurring() bool {
+	return t.Recurrence != "" && t.Recurrence != TaskRecurrenceOnce
+}
+
+// Remaining returns how many completions are left under the global cap, nil if uncapped
+func (t *Task) Remaining() *int64 {
+	if t.MaxCompletions == nil {
+		return nil
+	}
+	remaining := *t.MaxCompletions - t.Completions
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &remaining
+}
+
+// IsExpired reports whether the task's availability window has ended
+func (t *Task) IsExpired(now time.Time) bool {
+	return t.EndsAt != nil && !now.Before(*t.EndsAt)
+}
+
+// CheckAvailable returns an error if the task can't be completed at now because of
+// its availability window or global completion cap
+func (t *Task) CheckAvailable(now time.Time) error {
+	if t.StartsAt != nil && now.Before(*t.StartsAt) {
+		return &TaskNotStartedError{ID: t.ID, StartsAt: *t.StartsAt}
+	}
+	if t.IsExpired(now) {
+		return &TaskExpiredError{ID: t.ID}
+	}
+	if remaining := t.Remaining(); remaining != nil && *remaining == 0 {
+		return &TaskCapReachedError{ID: t.ID}
+	}
+	return nil
+}
+
+// CheckUserLimit returns an error if a user who completed the task completions
+// times can't complete it again
+func (t *Task) CheckUserLimit(userID int64, completions int) error {
+	if t.MaxPerUser != nil && completions >= *t.MaxPerUser {
+		return &TaskUserLimitReachedError{UserID: userID, TaskID: t.ID, Limit: *t.MaxPerUser}
+	}
+	return nil
+}
+
+// MissingPrerequisites returns prerequisite task IDs not among the completed task IDs
+func (t *Task) MissingPrerequisites(completed []int64) []int64 {
+	done := make(map[int64]bool, len(completed))
+	for _, id := range completed {
+		done[id] = true
+	}
+
+	var missing []int64
+	for _, id := range t.Prerequisites {
+		if !done[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// RequiresVerification reports whether completions stay pending until verified
+func (t *Task) RequiresVerification() bool {
+	return t.Verification == TaskVerificationManual || t.Verification == TaskVerificationCallback
+}
+
+// ValidateProof checks the proof submitted for a completion: tasks under manual review
+// need a proof URL or text, URLs must be absolute http(s) links
+func (t *Task) ValidateProof(proofURL, proofText string) error {
+	if proofURL != "" {
+		u, err := url.Parse(proofURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return &InvalidProofError{Reason: "proof_url must be an http or https URL"}
+		}
+	}
+	if len(proofText) > MaxProofTextLength {
+		return &InvalidProofError{Reason: fmt.Sprintf("proof_text must be at most %d characters", MaxProofTextLength)}
+	}
+	if t.Verification == TaskVerificationManual && proofURL == "" && strings.TrimSpace(proofText) == "" {
+		return &InvalidProofError{Reason: "proof_url or proof_text is required"}
+	}
+	return nil
+}
+
+// TaskFilter selects tasks of a category and/or with a tag, empty fields match any task
+type TaskFilter struct {
+	Category string
+	Tag      string
+}
+
+// Matches reports whether the task passes the filter
+func (f TaskFilter) Matches(t *Task) bool {
+	if f.Category != "" && (t.Category == nil || !strings.EqualFold(t.Category.Code, f.Category)) {
+		return false
+	}
+	return f.Tag == "" || t.HasTag(f.Tag)
+}
+
+// HasTag reports whether the task is tagged with tag (case-insensitive)
+func (t *Task) HasTag(tag string) bool {
+	for _, own := range t.Tags {
+		if strings.EqualFold(own, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// PeriodLength returns the nominal length of the task's recurrence period
+func (t *Task) PeriodLength() time.Duration {
+	switch t.Recurrence {
+	case TaskRecurrenceDaily:
+		return 24 * time.Hour
+	case TaskRecurrenceWeekly:
+		return 7 * 24 * time.Hour
+	case TaskRecurrenceHourly:
+		if t.RecurrenceHours != nil {
+			return time.Duration(*t.RecurrenceHours) * time.Hour
+		}
+	}
+	return 0
+}
+
+// UserTask represents a completed task by a user
+type UserTask struct {
+	ID           int64     `json:"id"`
 	UserID       int64     `json:"user_id"`
 	TaskID       int64     `json:"task_id"`
-	RewardPoints int64     `json:"reward_points"`
+	RewardPoints *int64    `json:"reward_points,omitempty"`
 	CompletedAt  time.Time `json:"completed_at"`
-	TaskCode     string    `json:"task_code"`
-	TaskTitle    string    `json:"task_title"`
+	PeriodStart  time.Time `json:"period_start"`
+	TaskVersion  int       `json:"task_version"`
+	Status       string    `json:"status"`
+	ProofURL     *string   `json:"proof_url,omitempty"`
+	ProofText    *string   `json:"proof_text,omitempty"`
+}
+
+// UserTaskWithDetails represents a completed task with task details and rewards earned by the completion.
+// RewardPoints are the points awarded for the completion, or the reward of the completed task version
+// while the completion is not rewarded
+type UserTaskWithDetails struct {
+	ID           int64        `json:"id"`
+	UserID       int64        `json:"user_id"`
+	TaskID       int64        `json:"task_id"`
+	RewardPoints int64        `json:"reward_points"`
+	ReviewedBy   *int64       `json:"reviewed_by,omitempty"`
+	CompletedAt  time.Time    `json:"completed_at"`
+	PeriodStart  time.Time    `json:"period_start"`
+	ReviewedAt   *time.Time   `json:"reviewed_at,omitempty"`
+	TaskVersion  int          `json:"task_version"`
+	TaskCode     string       `json:"task_code"`
+	TaskTitle    string       `json:"task_title"`
+	Recurrence   string       `json:"recurrence"`
+	Status       string       `json:"status"`
+	ProofURL     *string      `json:"proof_url,omitempty"`
+	ProofText    *string      `json:"proof_text,omitempty"`
+	ReviewNote   *string      `json:"review_note,omitempty"`
+	Rewards      []TaskReward `json:"rewards"`
 }
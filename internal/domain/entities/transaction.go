@@ -10,7 +10,10 @@ type Transaction struct {
 	UserID        int64     `json:"user_id"`
 	Delta         int64     `json:"delta"`
 	ReferenceID   *int64    `json:"reference_id,omitempty"`
+	UserTaskID    *int64    `json:"user_task_id,omitempty"`
+	CampaignID    *int64    `json:"campaign_id,omitempty"`
 	CreatedAt     time.Time `json:"created_at"`
 	Reason        string    `json:"reason"`
+	Currency      string    `json:"currency"`
 	ReferenceType *string   `json:"reference_type,omitempty"`
 }
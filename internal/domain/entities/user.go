@@ -2,13 +2,30 @@ package entities
 
 import "time"
 
+// User roles
+const (
+	// RoleUser is a regular user
+	RoleUser = "user"
+	// RoleModerator can review task submissions
+	RoleModerator = "moderator"
+	// RoleAdmin can do everything a moderator can and manage users
+	RoleAdmin = "admin"
+)
+
 // User represents a user in the system
 type User struct {
-	ID         int64     `json:"id"`
-	ReferrerID *int64    `json:"referrer_id,omitempty"`
-	CreatedAt  time.Time `json:"created_at"`
-	Balance    int64     `json:"balance"`
-	Username   string    `json:"username"`
+	Profile
+	AccountStatus
+	ID         int64      `json:"id"`
+	ReferrerID *int64     `json:"referrer_id,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+	Balance    int64      `json:"balance"`
+	Username   string     `json:"username"`
+	// NormalizedUsername is the key usernames are unique by, see NormalizeUsername
+	NormalizedUsername string     `json:"-"`
+	Role               string     `json:"role"`
+	Balances           []*Balance `json:"balances,omitempty"`
 }
 
 // UserWithReferrals represents a user with referral statistics
@@ -26,4 +43,5 @@ type UserWithBalance struct {
 	Points    int64     `json:"points"`
 	UpdatedAt time.Time `json:"updated_at"`
 	Username  string    `json:"username"`
+	Currency  string    `json:"currency"`
 }
@@ -0,0 +1,155 @@
+package entities
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// User search sort fields
+const (
+	UserSortCreatedAt = "created_at"
+	UserSortUsername  = "username"
+	UserSortBalance   = "balance"
+	UserSortReferrals = "referrals"
+)
+
+// User search username match modes
+const (
+	// UserMatchPrefix finds usernames starting with the query
+	UserMatchPrefix = "prefix"
+	// UserMatchSubstring finds usernames containing the query
+	UserMatchSubstring = "substring"
+)
+
+// MaxUserSearchLimit is the largest page of a user search
+const MaxUserSearchLimit = 100
+
+// UserSearch filters, sorts and pages users for support. Usernames are matched
+// on NormalizeUsername, so case variants and look-alikes are found too
+type UserSearch struct {
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	HasReferrer *bool
+	MinBalance  *int64
+	MaxBalance  *int64
+	// After continues the search after the last user of the previous page
+	After      *UserSearchCursor
+	Limit      int
+	Query      string
+	Match      string
+	Currency   string
+	Status     string
+	Sort       string
+	Descending bool
+}
+
+// Validate checks filters, sort and that the cursor belongs to the same sort
+func (s *UserSearch) Validate() error {
+	switch s.Match {
+	case UserMatchPrefix, UserMatchSubstring:
+	default:
+		return &InvalidUserSearchError{Reason: "match must be prefix or substring"}
+	}
+	switch s.Sort {
+	case UserSortCreatedAt, UserSortUsername, UserSortBalance, UserSortReferrals:
+	default:
+		return &InvalidUserSearchError{Reason: "sort must be created_at, username, balance or referrals"}
+	}
+	switch s.Status {
+	case "", UserStatusActive, UserStatusSuspended, UserStatusBanned, UserStatusDeleted:
+	default:
+		return &InvalidUserSearchError{Reason: "status must be active, suspended, banned or deleted"}
+	}
+
+	if s.Limit < 1 || s.Limit > MaxUserSearchLimit {
+		return &InvalidUserSearchError{Reason: "limit must be within 1-" + strconv.Itoa(MaxUserSearchLimit)}
+	}
+	if s.CreatedFrom != nil && s.CreatedTo != nil && s.CreatedTo.Before(*s.CreatedFrom) {
+		return &InvalidUserSearchError{Reason: "created_to is before created_from"}
+	}
+	if s.MinBalance != nil && s.MaxBalance != nil && *s.MaxBalance < *s.MinBalance {
+		return &InvalidUserSearchError{Reason: "max_balance is less than min_balance"}
+	}
+	if s.After != nil && (s.After.Sort != s.Sort || s.After.Descending != s.Descending) {
+		return &InvalidUserSearchError{Reason: "cursor belongs to another sort order"}
+	}
+	return nil
+}
+
+// NormalizedQuery returns the query in the form usernames are searched by
+func (s *UserSearch) NormalizedQuery() string {
+	return NormalizeUsername(strings.TrimSpace(s.Query))
+}
+
+// CursorAfter returns the cursor of the page following the user
+func (s *UserSearch) CursorAfter(user *UserSearchResult) *UserSearchCursor {
+	cursor := &UserSearchCursor{ID: user.ID, Sort: s.Sort, Descending: s.Descending}
+	switch s.Sort {
+	case UserSortCreatedAt:
+		cursor.Value = user.CreatedAt.Format(time.RFC3339Nano)
+	case UserSortUsername:
+		cursor.Value = user.Username
+	case UserSortBalance:
+		cursor.Value = strconv.FormatInt(user.Balance, 10)
+	case UserSortReferrals:
+		cursor.Value = strconv.FormatInt(user.ReferralCount, 10)
+	}
+	return cursor
+}
+
+// UserSearchCursor is the position of the last user of a page: the value of the
+// sort field and the ID breaking ties
+type UserSearchCursor struct {
+	ID         int64  `json:"id"`
+	Sort       string `json:"sort"`
+	Value      string `json:"value"`
+	Descending bool   `json:"desc,omitempty"`
+}
+
+// Encode returns the cursor as an opaque URL-safe token
+func (c *UserSearchCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeUserSearchCursor parses a token returned by UserSearchCursor.Encode
+func DecodeUserSearchCursor(token string) (*UserSearchCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, &InvalidUserSearchError{Reason: "invalid cursor"}
+	}
+
+	var cursor UserSearchCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID <= 0 {
+		return nil, &InvalidUserSearchError{Reason: "invalid cursor"}
+	}
+
+	// Values are compared in SQL, reject ones of the wrong type early
+	switch cursor.Sort {
+	case UserSortCreatedAt:
+		_, err = time.Parse(time.RFC3339Nano, cursor.Value)
+	case UserSortBalance, UserSortReferrals:
+		_, err = strconv.ParseInt(cursor.Value, 10, 64)
+	}
+	if err != nil {
+		return nil, &InvalidUserSearchError{Reason: "invalid cursor"}
+	}
+	return &cursor, nil
+}
+
+// UserSearchResult is a user found by a search with its balance in the searched
+// currency and the number of users it invited
+type UserSearchResult struct {
+	User
+	ReferralCount int64 `json:"referral_count"`
+}
+
+// UserSearchPage is a page of search results, NextCursor is empty on the last page
+type UserSearchPage struct {
+	Users      []*UserSearchResult `json:"users"`
+	Currency   string              `json:"currency"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
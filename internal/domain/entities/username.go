@@ -0,0 +1,257 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Username character sets
+const (
+	// UsernameCharsetASCII allows latin letters, digits, '_', '.' and '-'
+	UsernameCharsetASCII = "ascii"
+	// UsernameCharsetUnicode allows letters and digits of any single script, '_', '.' and '-'
+	UsernameCharsetUnicode = "unicode"
+)
+
+// Username policy rules reported in violations
+const (
+	UsernameRuleLength      = "length"
+	UsernameRuleCharset     = "charset"
+	UsernameRuleSeparators  = "separators"
+	UsernameRuleMixedScript = "mixed_script"
+	UsernameRuleReserved    = "reserved"
+	UsernameRuleBlocked     = "blocked"
+)
+
+// maxUsernameLength is the size of the users.username column
+const maxUsernameLength = 255
+
+// confusables folds characters that look like latin letters to them. Keep in sync
+// with the backfill of users.username_normalized in migrations/018_username_policy.up.sql
+var confusables = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'е': 'e', 'о': 'o', 'р': 'p', 'с': 'c', 'у': 'y', 'х': 'x',
+	'і': 'i', 'ј': 'j', 'ѕ': 's', 'ԁ': 'd', 'һ': 'h', 'ӏ': 'l', 'ԛ': 'q', 'ԝ': 'w',
+	// Greek
+	'ο': 'o', 'α': 'a', 'ν': 'v', 'ι': 'i', 'κ': 'k', 'ρ': 'p', 'υ': 'u', 'χ': 'x',
+	// Latin and digits
+	'ɡ': 'g', '0': 'o', '1': 'l',
+}
+
+// usernameScripts are scripts a unicode username may be written in, Japanese
+// scripts count as one
+var usernameScripts = []struct {
+	name   string
+	tables []*unicode.RangeTable
+}{
+	{"Latin", []*unicode.RangeTable{unicode.Latin}},
+	{"Cyrillic", []*unicode.RangeTable{unicode.Cyrillic}},
+	{"Greek", []*unicode.RangeTable{unicode.Greek}},
+	{"Armenian", []*unicode.RangeTable{unicode.Armenian}},
+	{"Georgian", []*unicode.RangeTable{unicode.Georgian}},
+	{"Hebrew", []*unicode.RangeTable{unicode.Hebrew}},
+	{"Arabic", []*unicode.RangeTable{unicode.Arabic}},
+	{"Devanagari", []*unicode.RangeTable{unicode.Devanagari}},
+	{"Thai", []*unicode.RangeTable{unicode.Thai}},
+	{"Hangul", []*unicode.RangeTable{unicode.Hangul}},
+	{"Han", []*unicode.RangeTable{unicode.Han, unicode.Hiragana, unicode.Katakana}},
+}
+
+// UsernameViolation describes a username policy rule a username breaks
+type UsernameViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// UsernamePolicy defines which usernames can be registered. Uniqueness is checked
+// on NormalizeUsername, so case variants and look-alikes of a taken username are taken
+type UsernamePolicy struct {
+	MinLength int
+	MaxLength int
+	Charset   string
+	// Reserved usernames can't be taken, compared normalized and without separators
+	Reserved []string
+	// Blocked words can't appear anywhere in a username
+	Blocked []string
+}
+
+// NewUsernamePolicy creates a username policy, reserved and blocked words are normalized
+func NewUsernamePolicy(minLength, maxLength int, charset string, reserved, blocked []string) (*UsernamePolicy, error) {
+	if minLength < 1 || maxLength < minLength || maxLength > maxUsernameLength {
+		return nil, fmt.Errorf("username length must be within 1-%d, got %d-%d", maxUsernameLength, minLength, maxLength)
+	}
+	if charset != UsernameCharsetASCII && charset != UsernameCharsetUnicode {
+		return nil, fmt.Errorf("unknown username charset %q", charset)
+	}
+
+	return &UsernamePolicy{
+		MinLength: minLength,
+		MaxLength: maxLength,
+		Charset:   charset,
+		Reserved:  usernameWords(reserved),
+		Blocked:   usernameWords(blocked),
+	}, nil
+}
+
+// usernameWords normalizes words and strips separators, dropping empty ones
+func usernameWords(words []string) []string {
+	var normalized []string
+	for _, word := range words {
+		if word = stripSeparators(NormalizeUsername(strings.TrimSpace(word))); word != "" {
+			normalized = append(normalized, word)
+		}
+	}
+	return normalized
+}
+
+// Validate returns InvalidUsernameError listing every rule the username breaks
+func (p *UsernamePolicy) Validate(username string) error {
+	var violations []UsernameViolation
+
+	if n := utf8.RuneCountInString(username); n < p.MinLength || n > p.MaxLength {
+		violations = append(violations, UsernameViolation{
+			Rule:    UsernameRuleLength,
+			Message: fmt.Sprintf("must be %d-%d characters long", p.MinLength, p.MaxLength),
+		})
+	}
+
+	if invalid := p.invalidChars(username); len(invalid) > 0 {
+		allowed := "latin letters, digits"
+		if p.Charset == UsernameCharsetUnicode {
+			allowed = "letters, digits"
+		}
+		violations = append(violations, UsernameViolation{
+			Rule:    UsernameRuleCharset,
+			Message: fmt.Sprintf("may contain only %s, '_', '.' and '-', found %s", allowed, strings.Join(invalid, ", ")),
+		})
+	}
+
+	if !validSeparators(username) {
+		violations = append(violations, UsernameViolation{
+			Rule:    UsernameRuleSeparators,
+			Message: "must start and end with a letter or digit and not contain consecutive '_', '.' or '-'",
+		})
+	}
+
+	if scripts := usernameScriptNames(username); len(scripts) > 1 {
+		violations = append(violations, UsernameViolation{
+			Rule:    UsernameRuleMixedScript,
+			Message: fmt.Sprintf("mixes %s letters", strings.Join(scripts, " and ")),
+		})
+	}
+
+	key := stripSeparators(NormalizeUsername(username))
+	for _, word := range p.Reserved {
+		if key == word {
+			violations = append(violations, UsernameViolation{Rule: UsernameRuleReserved, Message: "is reserved"})
+			break
+		}
+	}
+	for _, word := range p.Blocked {
+		if strings.Contains(key, word) {
+			violations = append(violations, UsernameViolation{Rule: UsernameRuleBlocked, Message: "contains a blocked word"})
+			break
+		}
+	}
+
+	if len(violations) > 0 {
+		return &InvalidUsernameError{Username: username, Violations: violations}
+	}
+	return nil
+}
+
+// invalidChars returns distinct quoted characters not allowed by the charset
+func (p *UsernamePolicy) invalidChars(username string) []string {
+	var invalid []string
+	seen := make(map[rune]bool)
+	for _, r := range username {
+		if isUsernameSeparator(r) || p.allowed(r) || seen[r] {
+			continue
+		}
+		seen[r] = true
+		invalid = append(invalid, fmt.Sprintf("%q", r))
+	}
+	return invalid
+}
+
+// allowed reports whether r is a letter or digit allowed by the charset
+func (p *UsernamePolicy) allowed(r rune) bool {
+	if r < utf8.RuneSelf {
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9'
+	}
+	if p.Charset != UsernameCharsetUnicode {
+		return false
+	}
+	return unicode.IsLetter(r) || unicode.Is(unicode.Nd, r) || unicode.Is(unicode.Mn, r)
+}
+
+// validSeparators reports whether separators are surrounded by letters or digits
+func validSeparators(username string) bool {
+	previous := '_'
+	for _, r := range username {
+		if isUsernameSeparator(r) && isUsernameSeparator(previous) {
+			return false
+		}
+		previous = r
+	}
+	return !isUsernameSeparator(previous)
+}
+
+// usernameScriptNames returns the scripts of the username letters in order of appearance
+func usernameScriptNames(username string) []string {
+	var names []string
+	for _, r := range username {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		for _, script := range usernameScripts {
+			if unicode.In(r, script.tables...) {
+				if !containsString(names, script.name) {
+					names = append(names, script.name)
+				}
+				break
+			}
+		}
+	}
+	return names
+}
+
+// NormalizeUsername returns the key usernames are unique by: NFKC normalized,
+// lower case, with look-alike characters folded to latin letters
+func NormalizeUsername(username string) string {
+	return strings.Map(func(r rune) rune {
+		if folded, ok := confusables[r]; ok {
+			return folded
+		}
+		return r
+	}, strings.ToLower(norm.NFKC.String(username)))
+}
+
+// stripSeparators removes '_', '.' and '-' from a username
+func stripSeparators(username string) string {
+	return strings.Map(func(r rune) rune {
+		if isUsernameSeparator(r) {
+			return -1
+		}
+		return r
+	}, username)
+}
+
+// isUsernameSeparator reports whether r is '_', '.' or '-'
+func isUsernameSeparator(r rune) bool {
+	return r == '_' || r == '.' || r == '-'
+}
+
+// containsString reports whether values contain value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+import "github.com/abdullinmm/user-management-api/internal/domain/entities"
+
+// EventBroker defines publishing and subscribing to live update events
+type EventBroker interface {
+	Publish(event entities.Event)
+	Subscribe(buffer int) (<-chan entities.Event, func())
+}
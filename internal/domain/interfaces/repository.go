@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"github.com/abdullinmm/user-management-api/internal/domain/entities"
 )
@@ -11,8 +12,21 @@ type UserRepository interface {
 	Create(ctx context.Context, user *entities.User) error
 	GetByID(ctx context.Context, id int64) (*entities.User, error)
 	GetByUsername(ctx context.Context, username string) (*entities.User, error)
+	GetByNormalizedUsername(ctx context.Context, normalized string) (*entities.User, error)
 	GetWithReferrals(ctx context.Context, id int64) (*entities.UserWithReferrals, error)
+	GetReferrals(ctx context.Context, referrerID int64) ([]*entities.Referral, error)
+	Search(ctx context.Context, search *entities.UserSearch) ([]*entities.UserSearchResult, error)
+	Import(ctx context.Context, rows []*entities.ImportRow, currency string) (int, []entities.ImportRowError, error)
 	SetReferrer(ctx context.Context, userID, referrerID int64) error
+	UpdateProfile(ctx context.Context, id int64, update func(user *entities.User) error) (*entities.User, error)
+	GetUsernameHistory(ctx context.Context, userID int64) ([]*entities.UsernameChange, error)
+	SetStatus(ctx context.Context, change *entities.UserStatusChange) error
+	GetStatusChanges(ctx context.Context, userID int64) ([]*entities.UserStatusChange, error)
+	SoftDelete(ctx context.Context, id int64) (time.Time, error)
+	Restore(ctx context.Context, id int64) (bool, error)
+	GetDeletedBefore(ctx context.Context, before time.Time, limit int) ([]int64, error)
+	Anonymize(ctx context.Context, id int64, requestedBy *int64) (*entities.DeletionReceipt, error)
+	GetDeletionReceipt(ctx context.Context, userID int64) (*entities.DeletionReceipt, error)
 }
 
 // TaskRepository defines operations for tasks
@@ -21,6 +35,8 @@ type TaskRepository interface {
 	GetByCode(ctx context.Context, code string) (*entities.Task, error)
 	GetActive(ctx context.Context) ([]*entities.Task, error)
 	GetAll(ctx context.Context) ([]*entities.Task, error)
+	GetTranslations(ctx context.Context, taskIDs []int64, locales []string) ([]*entities.TaskTranslation, error)
+	GetVersions(ctx context.Context, taskID int64) ([]*entities.TaskVersion, error)
 }
 
 // UserTaskRepository defines operations for user_tasks
@@ -28,18 +44,116 @@ type UserTaskRepository interface {
 	Create(ctx context.Context, userTask *entities.UserTask) error
 	IsCompleted(ctx context.Context, userID, taskID int64) (bool, error)
 	GetByUserID(ctx context.Context, userID int64) ([]*entities.UserTaskWithDetails, error)
-	GetAvailableTasksForUser(ctx context.Context, userID int64) ([]*entities.Task, error)
+	GetAvailableTasksForUser(ctx context.Context, audience *entities.Audience) ([]*entities.Task, error)
+	GetCompletedTaskIDs(ctx context.Context, userID int64) ([]int64, error)
+	GetByID(ctx context.Context, id int64) (*entities.UserTaskWithDetails, error)
+	GetPending(ctx context.Context, limit, offset int) ([]*entities.UserTaskWithDetails, error)
+	Review(ctx context.Context, id int64, status string, reviewerID *int64, note *string) (*entities.UserTask, error)
+	Reopen(ctx context.Context, id int64) error
+	GetForCurrentPeriod(ctx context.Context, userID, taskID int64) (*entities.UserTask, error)
+	SetRewardPoints(ctx context.Context, id, points int64) error
 }
 
 // BalanceRepository defines operations for balances
 type BalanceRepository interface {
-	GetByUserID(ctx context.Context, userID int64) (*entities.Balance, error)
-	UpdatePoints(ctx context.Context, userID int64, delta int64) error
-	GetLeaderboard(ctx context.Context, limit, offset int) ([]*entities.UserWithBalance, error)
+	GetByUserID(ctx context.Context, userID int64, currency string) (*entities.Balance, error)
+	GetAllByUserID(ctx context.Context, userID int64) ([]*entities.Balance, error)
+	UpdatePoints(ctx context.Context, userID int64, currency string, delta int64) error
+	GetLeaderboard(ctx context.Context, currency string, mode entities.RankingMode, limit, offset int) ([]*entities.LeaderboardEntry, error)
+	GetRank(ctx context.Context, userID int64, currency string, mode entities.RankingMode) (*entities.UserRank, error)
+	GetNeighbours(ctx context.Context, userID int64, currency string, mode entities.RankingMode, n int) ([]*entities.LeaderboardEntry, error)
+	GetEntry(ctx context.Context, userID int64, currency string) (*entities.LeaderboardEntry, error)
+}
+
+// LeaderboardRefresher reloads cached leaderboard entries after changes
+// that don't go through UpdatePoints
+type LeaderboardRefresher interface {
+	Refresh(ctx context.Context, userID int64, currency string)
+	Invalidate(currency string)
+}
+
+// SnapshotReader runs repository reads in one consistent snapshot of the database
+type SnapshotReader interface {
+	ReadSnapshot(ctx context.Context, read func(ctx context.Context) error) error
+}
+
+// DataExportRepository defines operations for personal data exports
+type DataExportRepository interface {
+	Create(ctx context.Context, export *entities.DataExport) error
+	GetByID(ctx context.Context, id int64, withContent bool) (*entities.DataExport, error)
+	GetByUserID(ctx context.Context, userID int64) ([]*entities.DataExport, error)
+	ClaimNext(ctx context.Context, staleAfter time.Duration) (*entities.DataExport, error)
+	Complete(ctx context.Context, id int64, content []byte, expiresAt time.Time) error
+	Fail(ctx context.Context, id int64, reason string) error
+	ExpireBefore(ctx context.Context, now time.Time) (int64, error)
+}
+
+// CurrencyRepository defines operations for currencies
+type CurrencyRepository interface {
+	GetByCode(ctx context.Context, code string) (*entities.Currency, error)
+	GetAll(ctx context.Context) ([]*entities.Currency, error)
 }
 
 // TransactionRepository defines operations for transactions
 type TransactionRepository interface {
 	Create(ctx context.Context, transaction *entities.Transaction) error
 	GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]*entities.Transaction, error)
+	GetByUserIDAfter(ctx context.Context, userID, afterID int64, limit int) ([]*entities.Transaction, error)
+	GetEarnedLeaderboard(ctx context.Context, currency string, since *time.Time, mode entities.RankingMode, limit, offset int) ([]*entities.LeaderboardEntry, error)
+}
+
+// LevelRepository defines operations for level-up events
+type LevelRepository interface {
+	CreateLevelUp(ctx context.Context, levelUp *entities.LevelUp) error
+	GetLevelUpsByUserID(ctx context.Context, userID int64) ([]*entities.LevelUp, error)
+}
+
+// BadgeRepository defines operations for badges and earned badges
+type BadgeRepository interface {
+	GetActive(ctx context.Context) ([]*entities.Badge, error)
+	Award(ctx context.Context, userID, badgeID int64) (bool, error)
+	GetByUserID(ctx context.Context, userID int64) ([]*entities.UserBadge, error)
+}
+
+// StreakRepository defines operations for streaks and streak freezes
+type StreakRepository interface {
+	Get(ctx context.Context, userID, taskID int64) (*entities.Streak, error)
+	Save(ctx context.Context, streak *entities.Streak) error
+	GetByUserID(ctx context.Context, userID int64) ([]*entities.Streak, error)
+	GetFreezes(ctx context.Context, userID int64) (int, error)
+	AddFreezes(ctx context.Context, userID int64, delta int) error
+	PurchaseFreeze(ctx context.Context, userID int64, payment *entities.Transaction) (int, error)
+}
+
+// QuestChainRepository defines operations for quest chains
+type QuestChainRepository interface {
+	GetByID(ctx context.Context, id int64) (*entities.QuestChain, error)
+	GetUserChains(ctx context.Context, userID int64) ([]*entities.UserQuestChain, error)
+	Complete(ctx context.Context, userID, chainID int64) (bool, error)
+}
+
+// PartnerRepository defines operations for partners and their callback nonces
+type PartnerRepository interface {
+	GetByCode(ctx context.Context, code string) (*entities.Partner, error)
+	UseNonce(ctx context.Context, partnerID int64, nonce string) (bool, error)
+	PurgeNonces(ctx context.Context, before time.Time) (int64, error)
+}
+
+// SegmentRepository defines operations for user segments and task audience
+type SegmentRepository interface {
+	GetAudience(ctx context.Context, userID int64) (*entities.Audience, error)
+	GetAll(ctx context.Context) ([]*entities.Segment, error)
+	GetByCode(ctx context.Context, code string) (*entities.Segment, error)
+	Save(ctx context.Context, segment *entities.Segment) error
+	AddMembers(ctx context.Context, segmentID int64, userIDs []int64) (int64, error)
+	RemoveMember(ctx context.Context, segmentID, userID int64) (bool, error)
+}
+
+// CampaignRepository defines operations for reward campaigns
+type CampaignRepository interface {
+	GetRunning(ctx context.Context) ([]*entities.Campaign, error)
+	GetAll(ctx context.Context) ([]*entities.Campaign, error)
+	Create(ctx context.Context, campaign *entities.Campaign) error
+	Spend(ctx context.Context, campaignID, amount int64) (int64, error)
+	Refund(ctx context.Context, campaignID, amount int64) error
 }
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/abdullinmm/user-management-api/internal/middleware"
+	"github.com/abdullinmm/user-management-api/internal/usecase"
+	"github.com/go-chi/chi/v5"
+)
+
+// BadgeHandler handles badge-related HTTP requests
+type BadgeHandler struct {
+	badgeUC *usecase.BadgeUseCase
+}
+
+// NewBadgeHandler creates a new badge handler
+func NewBadgeHandler(badgeUC *usecase.BadgeUseCase) *BadgeHandler {
+	return &BadgeHandler{
+		badgeUC: badgeUC,
+	}
+}
+
+// ListUserBadges returns badges earned by a user
+// GET /users/{id}/badges
+func (h *BadgeHandler) ListUserBadges(w http.ResponseWriter, r *http.Request) {
+	userIDStr := chi.URLParam(r, "id")
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	// Verify authenticated user matches requested user
+	authUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok || authUserID != userID {
+		respondError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	badges, err := h.badgeUC.GetUserBadges(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to fetch badges")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, badges)
+}
@@ -1,10 +1,14 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/middleware"
 	"github.com/abdullinmm/user-management-api/internal/usecase"
+	"github.com/go-chi/chi/v5"
 )
 
 // BalanceHandler handles balance-related HTTP requests
@@ -19,8 +23,9 @@ func NewBalanceHandler(balanceUC *usecase.BalanceUseCase) *BalanceHandler {
 	}
 }
 
-// Leaderboard returns top users by balance
-// GET /users/leaderboard
+// Leaderboard returns top users by balance in the requested currency,
+// or by points earned in a window when window is set
+// GET /users/leaderboard?currency=points&window=daily|weekly|monthly|all_time&ranking=competition|dense
 func (h *BalanceHandler) Leaderboard(w http.ResponseWriter, r *http.Request) {
 	// Get limit from query params (default 10)
 	limitStr := r.URL.Query().Get("limit")
@@ -40,11 +45,98 @@ func (h *BalanceHandler) Leaderboard(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	leaderboard, err := h.balanceUC.GetLeaderboard(r.Context(), limit, offset)
+	currency := r.URL.Query().Get("currency")
+
+	mode, err := entities.ParseRankingMode(r.URL.Query().Get("ranking"))
 	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var leaderboard []*entities.LeaderboardEntry
+	if windowStr := r.URL.Query().Get("window"); windowStr != "" {
+		window, parseErr := entities.ParseLeaderboardWindow(windowStr)
+		if parseErr != nil {
+			respondError(w, http.StatusBadRequest, parseErr.Error())
+			return
+		}
+		leaderboard, err = h.balanceUC.GetWindowLeaderboard(r.Context(), currency, window, mode, limit, offset)
+	} else {
+		leaderboard, err = h.balanceUC.GetLeaderboard(r.Context(), currency, mode, limit, offset)
+	}
+	if err != nil {
+		var currencyErr *entities.CurrencyNotFoundError
+		if errors.As(err, &currencyErr) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		respondError(w, http.StatusInternalServerError, "failed to fetch leaderboard")
 		return
 	}
 
 	respondJSON(w, http.StatusOK, leaderboard)
 }
+
+// Rank returns the user's rank and percentile, optionally with neighbours above and below
+// GET /users/{id}/rank?currency=points&ranking=competition|dense&neighbours=5
+func (h *BalanceHandler) Rank(w http.ResponseWriter, r *http.Request) {
+	userIDStr := chi.URLParam(r, "id")
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	// Verify authenticated user matches requested user
+	authUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok || authUserID != userID {
+		respondError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	mode, err := entities.ParseRankingMode(r.URL.Query().Get("ranking"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Get neighbours count from query params (default 0, max 50)
+	neighbours := 0
+	if neighboursStr := r.URL.Query().Get("neighbours"); neighboursStr != "" {
+		n, err := strconv.Atoi(neighboursStr)
+		if err != nil || n < 0 || n > 50 {
+			respondError(w, http.StatusBadRequest, "neighbours must be between 0 and 50")
+			return
+		}
+		neighbours = n
+	}
+
+	rank, err := h.balanceUC.GetUserRank(r.Context(), userID, r.URL.Query().Get("currency"), mode, neighbours)
+	if err != nil {
+		var currencyErr *entities.CurrencyNotFoundError
+		var notFoundErr *entities.UserNotFoundError
+		switch {
+		case errors.As(err, &currencyErr):
+			respondError(w, http.StatusBadRequest, err.Error())
+		case errors.As(err, &notFoundErr):
+			respondError(w, http.StatusNotFound, "user not ranked")
+		default:
+			respondError(w, http.StatusInternalServerError, "failed to fetch rank")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rank)
+}
+
+// Currencies returns all available currencies
+// GET /currencies
+func (h *BalanceHandler) Currencies(w http.ResponseWriter, r *http.Request) {
+	currencies, err := h.balanceUC.GetCurrencies(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to fetch currencies")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, currencies)
+}
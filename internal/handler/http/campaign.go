@@ -0,0 +1,99 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/usecase"
+)
+
+// CampaignHandler handles reward campaign HTTP requests
+type CampaignHandler struct {
+	campaignUC *usecase.CampaignUseCase
+}
+
+// NewCampaignHandler creates a new campaign handler
+func NewCampaignHandler(campaignUC *usecase.CampaignUseCase) *CampaignHandler {
+	return &CampaignHandler{
+		campaignUC: campaignUC,
+	}
+}
+
+// ListRunning returns campaigns boosting rewards right now
+// GET /campaigns
+func (h *CampaignHandler) ListRunning(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.campaignUC.GetRunning)
+}
+
+// ListAll returns all campaigns including finished ones
+// GET /admin/campaigns
+func (h *CampaignHandler) ListAll(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.campaignUC.GetAll)
+}
+
+// list responds with campaigns returned by fetch
+func (h *CampaignHandler) list(w http.ResponseWriter, r *http.Request, fetch func(ctx context.Context) ([]*entities.Campaign, error)) {
+	campaigns, err := fetch(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to fetch campaigns")
+		return
+	}
+	if campaigns == nil {
+		campaigns = []*entities.Campaign{}
+	}
+
+	respondJSON(w, http.StatusOK, campaigns)
+}
+
+// Create starts a new campaign
+// POST /admin/campaigns
+func (h *CampaignHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Code               string    `json:"code"`
+		Title              string    `json:"title"`
+		Multiplier         float64   `json:"multiplier"`
+		AppliesToTasks     *bool     `json:"applies_to_tasks"`
+		AppliesToReferrals bool      `json:"applies_to_referrals"`
+		Categories         []string  `json:"categories"`
+		Budget             *int64    `json:"budget"`
+		StartsAt           time.Time `json:"starts_at"`
+		EndsAt             time.Time `json:"ends_at"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	campaign := &entities.Campaign{
+		Code:               req.Code,
+		Title:              req.Title,
+		Multiplier:         req.Multiplier,
+		AppliesToTasks:     req.AppliesToTasks == nil || *req.AppliesToTasks,
+		AppliesToReferrals: req.AppliesToReferrals,
+		Categories:         req.Categories,
+		Budget:             req.Budget,
+		StartsAt:           req.StartsAt,
+		EndsAt:             req.EndsAt,
+	}
+
+	campaign, err := h.campaignUC.Create(r.Context(), campaign)
+	if err != nil {
+		var invalidErr *entities.InvalidCampaignError
+		var existsErr *entities.CampaignExistsError
+		switch {
+		case errors.As(err, &invalidErr):
+			respondError(w, http.StatusBadRequest, err.Error())
+		case errors.As(err, &existsErr):
+			respondError(w, http.StatusConflict, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, "failed to create campaign")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, campaign)
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/middleware"
+	"github.com/abdullinmm/user-management-api/internal/usecase"
+	"github.com/go-chi/chi/v5"
+)
+
+// ErasureHandler handles account deletion and erasure HTTP requests
+type ErasureHandler struct {
+	erasureUC *usecase.ErasureUseCase
+}
+
+// NewErasureHandler creates a new erasure handler
+func NewErasureHandler(erasureUC *usecase.ErasureUseCase) *ErasureHandler {
+	return &ErasureHandler{
+		erasureUC: erasureUC,
+	}
+}
+
+// DeleteAccount soft deletes the authenticated user, who is anonymised after the grace period
+// DELETE /users/{id}
+func (h *ErasureHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	// Verify authenticated user matches requested user
+	authUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok || authUserID != userID {
+		respondError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	deletion, err := h.erasureUC.DeleteAccount(r.Context(), userID)
+	if err != nil {
+		h.respondErasureError(w, err, "failed to delete account")
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, deletion)
+}
+
+// Restore undeletes a soft deleted user during the grace period
+// POST /admin/users/{id}/restore
+func (h *ErasureHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	if err := h.erasureUC.RestoreAccount(r.Context(), userID); err != nil {
+		h.respondErasureError(w, err, "failed to restore account")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "account restored",
+	})
+}
+
+// Erase anonymises a user right away and returns the deletion receipt
+// POST /admin/users/{id}/erasure
+func (h *ErasureHandler) Erase(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	adminID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	receipt, err := h.erasureUC.Erase(r.Context(), userID, &adminID)
+	if err != nil {
+		h.respondErasureError(w, err, "failed to erase user")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, receipt)
+}
+
+// GetReceipt returns the deletion receipt of an anonymised user
+// GET /admin/users/{id}/erasure
+func (h *ErasureHandler) GetReceipt(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	receipt, err := h.erasureUC.GetReceipt(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to fetch deletion receipt")
+		return
+	}
+	if receipt == nil {
+		respondError(w, http.StatusNotFound, "user is not erased")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, receipt)
+}
+
+// respondErasureError maps account deletion errors to HTTP statuses
+func (h *ErasureHandler) respondErasureError(w http.ResponseWriter, err error, message string) {
+	var notFoundErr *entities.UserNotFoundError
+	if errors.As(err, &notFoundErr) {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	respondError(w, http.StatusInternalServerError, message)
+}
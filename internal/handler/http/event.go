@@ -0,0 +1,94 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/abdullinmm/user-management-api/internal/middleware"
+	"github.com/abdullinmm/user-management-api/internal/usecase"
+	"github.com/go-chi/chi/v5"
+)
+
+// heartbeatInterval keeps idle streams alive through proxies
+const heartbeatInterval = 15 * time.Second
+
+// EventHandler handles live update streams
+type EventHandler struct {
+	eventUC *usecase.EventUseCase
+}
+
+// NewEventHandler creates a new event handler
+func NewEventHandler(eventUC *usecase.EventUseCase) *EventHandler {
+	return &EventHandler{
+		eventUC: eventUC,
+	}
+}
+
+// Stream pushes leaderboard changes and the user's balance and transaction events
+// as Server-Sent Events
+// GET /users/{id}/events
+func (h *EventHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	userIDStr := chi.URLParam(r, "id")
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	// Verify authenticated user matches requested user
+	authUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok || authUserID != userID {
+		respondError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	// Streams outlive the server write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		respondError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	events := h.eventUC.Subscribe(r.Context(), userID)
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("failed to encode event: %v", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
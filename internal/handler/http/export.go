@@ -0,0 +1,167 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/middleware"
+	"github.com/abdullinmm/user-management-api/internal/usecase"
+	"github.com/go-chi/chi/v5"
+)
+
+// ExportHandler handles personal data export HTTP requests
+type ExportHandler struct {
+	exportUC *usecase.ExportUseCase
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(exportUC *usecase.ExportUseCase) *ExportHandler {
+	return &ExportHandler{
+		exportUC: exportUC,
+	}
+}
+
+// Request queues an export of everything stored about the user
+// POST /users/{id}/exports
+func (h *ExportHandler) Request(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorizedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	// Format is optional, JSON by default
+	req := struct {
+		Format string `json:"format"`
+	}{Format: entities.DataExportFormatJSON}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	export, err := h.exportUC.RequestExport(r.Context(), userID, req.Format)
+	if err != nil {
+		h.respondExportError(w, err, "failed to request data export")
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, export)
+}
+
+// List returns the user's data exports, latest first
+// GET /users/{id}/exports
+func (h *ExportHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorizedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	exports, err := h.exportUC.GetExports(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to fetch data exports")
+		return
+	}
+	if exports == nil {
+		exports = []*entities.DataExport{}
+	}
+
+	respondJSON(w, http.StatusOK, exports)
+}
+
+// Get returns the status of a data export
+// GET /users/{id}/exports/{exportID}
+func (h *ExportHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorizedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	exportID, err := strconv.ParseInt(chi.URLParam(r, "exportID"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid export ID")
+		return
+	}
+
+	export, err := h.exportUC.GetExport(r.Context(), userID, exportID)
+	if err != nil {
+		h.respondExportError(w, err, "failed to fetch data export")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, export)
+}
+
+// Download sends the archive of a ready data export until it expires
+// GET /users/{id}/exports/{exportID}/download
+func (h *ExportHandler) Download(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorizedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	exportID, err := strconv.ParseInt(chi.URLParam(r, "exportID"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid export ID")
+		return
+	}
+
+	export, err := h.exportUC.Download(r.Context(), userID, exportID)
+	if err != nil {
+		h.respondExportError(w, err, "failed to download data export")
+		return
+	}
+
+	w.Header().Set("Content-Type", export.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.FileName()))
+	w.Header().Set("Content-Length", strconv.Itoa(len(export.Content)))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(export.Content); err != nil {
+		log.Printf("failed to send data export %d: %v", export.ID, err)
+	}
+}
+
+// authorizedUserID parses the user ID from the URL and checks that it is the
+// authenticated user, responding with an error otherwise
+func (h *ExportHandler) authorizedUserID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid user ID")
+		return 0, false
+	}
+
+	// Verify authenticated user matches requested user
+	authUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok || authUserID != userID {
+		respondError(w, http.StatusForbidden, "access denied")
+		return 0, false
+	}
+
+	return userID, true
+}
+
+// respondExportError maps data export errors to HTTP statuses
+func (h *ExportHandler) respondExportError(w http.ResponseWriter, err error, message string) {
+	var formatErr *entities.InvalidExportFormatError
+	var notFoundErr *entities.DataExportNotFoundError
+	var notReadyErr *entities.DataExportNotReadyError
+	var expiredErr *entities.DataExportExpiredError
+	switch {
+	case errors.As(err, &formatErr):
+		respondError(w, http.StatusBadRequest, err.Error())
+	case errors.As(err, &notFoundErr):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.As(err, &notReadyErr):
+		respondError(w, http.StatusConflict, err.Error())
+	case errors.As(err, &expiredErr):
+		respondError(w, http.StatusGone, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, message)
+	}
+}
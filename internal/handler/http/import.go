@@ -0,0 +1,81 @@
+package http
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/usecase"
+)
+
+// maxImportSize limits the size of an uploaded import file, larger files are
+// imported with cmd/import
+const maxImportSize = 64 << 20
+
+// ImportHandler handles bulk user import HTTP requests
+type ImportHandler struct {
+	importUC *usecase.ImportUseCase
+}
+
+// NewImportHandler creates a new import handler
+func NewImportHandler(importUC *usecase.ImportUseCase) *ImportHandler {
+	return &ImportHandler{
+		importUC: importUC,
+	}
+}
+
+// Import creates users from a CSV or NDJSON request body and returns the import
+// report, the format is taken from the query or the Content-Type
+// POST /admin/users/import?format=csv|ndjson&currency=points
+func (h *ImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		switch mediaType {
+		case "text/csv":
+			format = entities.ImportFormatCSV
+		case "application/x-ndjson", "application/ndjson":
+			format = entities.ImportFormatNDJSON
+		default:
+			respondError(w, http.StatusUnsupportedMediaType, "Content-Type must be text/csv or application/x-ndjson")
+			return
+		}
+	}
+
+	if r.ContentLength > maxImportSize {
+		respondError(w, http.StatusRequestEntityTooLarge, "import file is too large, use cmd/import")
+		return
+	}
+
+	// Imports outlive the server read and write timeouts
+	rc := http.NewResponseController(w)
+	if err := rc.SetReadDeadline(time.Time{}); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to extend request deadline")
+		return
+	}
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to extend request deadline")
+		return
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxImportSize)
+	report, err := h.importUC.Import(r.Context(), body, format, r.URL.Query().Get("currency"))
+	if err != nil {
+		var importErr *entities.InvalidImportError
+		var currencyErr *entities.CurrencyNotFoundError
+		var sizeErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &importErr), errors.As(err, &currencyErr):
+			respondError(w, http.StatusBadRequest, err.Error())
+		case errors.As(err, &sizeErr):
+			respondError(w, http.StatusRequestEntityTooLarge, "import file is too large, use cmd/import")
+		default:
+			respondError(w, http.StatusInternalServerError, "failed to import users")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,183 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/middleware"
+	"github.com/abdullinmm/user-management-api/internal/usecase"
+	"github.com/go-chi/chi/v5"
+)
+
+// ModerationHandler handles task submission review and user status HTTP requests
+type ModerationHandler struct {
+	taskUC *usecase.TaskUseCase
+	userUC *usecase.UserUseCase
+}
+
+// NewModerationHandler creates a new moderation handler
+func NewModerationHandler(taskUC *usecase.TaskUseCase, userUC *usecase.UserUseCase) *ModerationHandler {
+	return &ModerationHandler{
+		taskUC: taskUC,
+		userUC: userUC,
+	}
+}
+
+// ListPending returns submissions awaiting verification, oldest first
+// GET /moderation/submissions?limit=20&offset=0
+func (h *ModerationHandler) ListPending(w http.ResponseWriter, r *http.Request) {
+	// Get limit from query params (default 20, max 100)
+	limit := 20
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+		limit = min(l, 100)
+	}
+
+	// Get offset from query params (default 0)
+	offset := 0
+	if o, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && o >= 0 {
+		offset = o
+	}
+
+	submissions, err := h.taskUC.GetPendingSubmissions(r.Context(), limit, offset)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to fetch submissions")
+		return
+	}
+	if submissions == nil {
+		submissions = []*entities.UserTaskWithDetails{}
+	}
+
+	respondJSON(w, http.StatusOK, submissions)
+}
+
+// Approve approves a pending submission and awards its rewards
+// POST /moderation/submissions/{id}/approve
+func (h *ModerationHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, true)
+}
+
+// Reject rejects a pending submission, the user can submit the task again
+// POST /moderation/submissions/{id}/reject
+func (h *ModerationHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, false)
+}
+
+// review applies a moderator decision to a submission
+func (h *ModerationHandler) review(w http.ResponseWriter, r *http.Request, approve bool) {
+	submissionID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid submission ID")
+		return
+	}
+
+	reviewerID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	// Review note is optional
+	var req struct {
+		Note string `json:"note"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	userTask, err := h.taskUC.ReviewSubmission(r.Context(), submissionID, approve, &reviewerID, req.Note)
+	if err != nil {
+		var notFoundErr *entities.SubmissionNotFoundError
+		var notPendingErr *entities.SubmissionNotPendingError
+		var restrictedErr *entities.UserRestrictedError
+		switch {
+		case errors.As(err, &notFoundErr):
+			respondError(w, http.StatusNotFound, err.Error())
+		case errors.As(err, &notPendingErr), errors.As(err, &restrictedErr):
+			respondError(w, http.StatusConflict, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, "failed to review submission")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, userTask)
+}
+
+// SetUserStatus suspends, bans or reactivates a user, restrictions need a reason
+// and may expire
+// PUT /moderation/users/{id}/status
+func (h *ModerationHandler) SetUserStatus(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	actorID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	var req struct {
+		ExpiresAt *time.Time `json:"expires_at"`
+		Status    string     `json:"status"`
+		Reason    string     `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	change, err := h.userUC.SetStatus(r.Context(), &entities.UserStatusChange{
+		UserID:    userID,
+		ActorID:   &actorID,
+		ExpiresAt: req.ExpiresAt,
+		Status:    req.Status,
+		Reason:    strings.TrimSpace(req.Reason),
+	})
+	if err != nil {
+		var invalidErr *entities.InvalidStatusChangeError
+		var notFoundErr *entities.UserNotFoundError
+		switch {
+		case errors.As(err, &invalidErr):
+			respondError(w, http.StatusBadRequest, err.Error())
+		case errors.As(err, &notFoundErr):
+			respondError(w, http.StatusNotFound, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, "failed to change user status")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, change)
+}
+
+// ListUserStatusChanges returns the status change audit of a user, latest first
+// GET /moderation/users/{id}/status-changes
+func (h *ModerationHandler) ListUserStatusChanges(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	changes, err := h.userUC.GetStatusChanges(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to fetch status changes")
+		return
+	}
+	if changes == nil {
+		changes = []*entities.UserStatusChange{}
+	}
+
+	respondJSON(w, http.StatusOK, changes)
+}
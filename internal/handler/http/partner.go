@@ -0,0 +1,65 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/usecase"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxCallbackBodySize limits the size of partner callback payloads
+const maxCallbackBodySize = 64 << 10
+
+// PartnerHandler handles partner callback HTTP requests
+type PartnerHandler struct {
+	partnerUC *usecase.PartnerUseCase
+}
+
+// NewPartnerHandler creates a new partner handler
+func NewPartnerHandler(partnerUC *usecase.PartnerUseCase) *PartnerHandler {
+	return &PartnerHandler{
+		partnerUC: partnerUC,
+	}
+}
+
+// Callback completes a task verified by a partner. The raw body is signed with the
+// partner secret (hex HMAC-SHA256) and the signature is passed in X-Signature
+// POST /partners/{code}/callbacks
+func (h *PartnerHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCallbackBodySize))
+	if err != nil {
+		respondError(w, http.StatusRequestEntityTooLarge, "request body too large")
+		return
+	}
+
+	userTask, err := h.partnerUC.HandleCallback(r.Context(), chi.URLParam(r, "code"), payload, r.Header.Get("X-Signature"))
+	if err != nil {
+		var partnerErr *entities.PartnerNotFoundError
+		var signatureErr *entities.InvalidSignatureError
+		var callbackErr *entities.InvalidCallbackError
+		var replayErr *entities.CallbackReplayError
+		var mismatchErr *entities.TaskPartnerMismatchError
+		var userErr *entities.UserNotFoundError
+		switch {
+		case errors.As(err, &partnerErr), errors.As(err, &signatureErr):
+			respondError(w, http.StatusUnauthorized, err.Error())
+		case errors.As(err, &mismatchErr):
+			respondError(w, http.StatusForbidden, err.Error())
+		case errors.As(err, &replayErr):
+			respondError(w, http.StatusConflict, err.Error())
+		case errors.As(err, &userErr):
+			respondError(w, http.StatusNotFound, err.Error())
+		case errors.As(err, &callbackErr):
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			// Task can't be completed: inactive, expired, locked or already completed
+			respondError(w, http.StatusUnprocessableEntity, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, userTask)
+}
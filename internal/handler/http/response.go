@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
 )
 
 // ErrorResponse represents an error response
@@ -11,6 +13,12 @@ type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
+// UsernameErrorResponse represents a username policy error with the broken rules
+type UsernameErrorResponse struct {
+	Error      string                       `json:"error"`
+	Violations []entities.UsernameViolation `json:"violations"`
+}
+
 // respondJSON sends a JSON response
 func respondJSON(w http.ResponseWriter, status int, payload interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -26,3 +34,8 @@ func respondJSON(w http.ResponseWriter, status int, payload interface{}) {
 func respondError(w http.ResponseWriter, status int, message string) {
 	respondJSON(w, status, ErrorResponse{Error: message})
 }
+
+// respondUsernameError sends a username policy error listing the broken rules
+func respondUsernameError(w http.ResponseWriter, status int, err *entities.InvalidUsernameError) {
+	respondJSON(w, status, UsernameErrorResponse{Error: err.Error(), Violations: err.Violations})
+}
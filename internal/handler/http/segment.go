@@ -0,0 +1,119 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/usecase"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxSegmentBatch limits the number of users added to a segment in one request
+const maxSegmentBatch = 10000
+
+// SegmentHandler handles user segment management HTTP requests
+type SegmentHandler struct {
+	segmentUC *usecase.SegmentUseCase
+}
+
+// NewSegmentHandler creates a new segment handler
+func NewSegmentHandler(segmentUC *usecase.SegmentUseCase) *SegmentHandler {
+	return &SegmentHandler{
+		segmentUC: segmentUC,
+	}
+}
+
+// List returns all segments with their member counts
+// GET /admin/segments
+func (h *SegmentHandler) List(w http.ResponseWriter, r *http.Request) {
+	segments, err := h.segmentUC.GetSegments(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to fetch segments")
+		return
+	}
+	if segments == nil {
+		segments = []*entities.Segment{}
+	}
+
+	respondJSON(w, http.StatusOK, segments)
+}
+
+// Save creates a segment or renames an existing one
+// PUT /admin/segments/{code}
+func (h *SegmentHandler) Save(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Title string `json:"title"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	segment, err := h.segmentUC.SaveSegment(r.Context(), chi.URLParam(r, "code"), req.Title)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, segment)
+}
+
+// AddMembers adds users to a segment
+// POST /admin/segments/{code}/members
+func (h *SegmentHandler) AddMembers(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserIDs []int64 `json:"user_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.UserIDs) > maxSegmentBatch {
+		respondError(w, http.StatusBadRequest, "too many user_ids, max "+strconv.Itoa(maxSegmentBatch))
+		return
+	}
+
+	added, err := h.segmentUC.AddMembers(r.Context(), chi.URLParam(r, "code"), req.UserIDs)
+	if err != nil {
+		h.respondSegmentError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]int64{
+		"added": added,
+	})
+}
+
+// RemoveMember removes a user from a segment
+// DELETE /admin/segments/{code}/members/{userID}
+func (h *SegmentHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "userID"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	if err := h.segmentUC.RemoveMember(r.Context(), chi.URLParam(r, "code"), userID); err != nil {
+		h.respondSegmentError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "user removed from segment",
+	})
+}
+
+// respondSegmentError maps segment errors to HTTP statuses
+func (h *SegmentHandler) respondSegmentError(w http.ResponseWriter, err error) {
+	var segmentErr *entities.SegmentNotFoundError
+	var memberErr *entities.SegmentMemberNotFoundError
+	switch {
+	case errors.As(err, &segmentErr), errors.As(err, &memberErr):
+		respondError(w, http.StatusNotFound, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, "failed to update segment")
+	}
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/middleware"
+	"github.com/abdullinmm/user-management-api/internal/usecase"
+	"github.com/go-chi/chi/v5"
+)
+
+// StreakHandler handles streak-related HTTP requests
+type StreakHandler struct {
+	streakUC *usecase.StreakUseCase
+}
+
+// NewStreakHandler creates a new streak handler
+func NewStreakHandler(streakUC *usecase.StreakUseCase) *StreakHandler {
+	return &StreakHandler{
+		streakUC: streakUC,
+	}
+}
+
+// GetStreaks returns user streaks and available streak freezes
+// GET /users/{id}/streaks
+func (h *StreakHandler) GetStreaks(w http.ResponseWriter, r *http.Request) {
+	userIDStr := chi.URLParam(r, "id")
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	// Verify authenticated user matches requested user
+	authUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok || authUserID != userID {
+		respondError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	summary, err := h.streakUC.GetSummary(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to fetch streaks")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, summary)
+}
+
+// PurchaseFreeze buys a streak freeze for points
+// POST /users/{id}/streak-freezes
+func (h *StreakHandler) PurchaseFreeze(w http.ResponseWriter, r *http.Request) {
+	userIDStr := chi.URLParam(r, "id")
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	// Verify authenticated user matches requested user
+	authUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok || authUserID != userID {
+		respondError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	available, err := h.streakUC.PurchaseFreeze(r.Context(), userID)
+	if err != nil {
+		var balanceErr *entities.InsufficientBalanceError
+		if errors.As(err, &balanceErr) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to purchase streak freeze")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message":           "streak freeze purchased",
+		"freezes_available": available,
+	})
+}
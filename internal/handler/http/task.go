@@ -2,9 +2,11 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
 	"github.com/abdullinmm/user-management-api/internal/middleware"
 	"github.com/abdullinmm/user-management-api/internal/usecase"
 	"github.com/go-chi/chi/v5"
@@ -22,7 +24,7 @@ func NewTaskHandler(taskUC *usecase.TaskUseCase) *TaskHandler {
 	}
 }
 
-// CompleteTask marks a task as completed for a user
+// CompleteTask marks a task as completed for a user or submits it for verification
 // POST /users/{id}/task/complete
 func (h *TaskHandler) CompleteTask(w http.ResponseWriter, r *http.Request) {
 	userIDStr := chi.URLParam(r, "id")
@@ -40,7 +42,9 @@ func (h *TaskHandler) CompleteTask(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		TaskID int64 `json:"task_id"`
+		TaskID    int64  `json:"task_id"`
+		ProofURL  string `json:"proof_url"`
+		ProofText string `json:"proof_text"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondError(w, http.StatusBadRequest, "invalid request body")
@@ -52,20 +56,38 @@ func (h *TaskHandler) CompleteTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.taskUC.CompleteTask(r.Context(), userID, req.TaskID); err != nil {
+	userTask, err := h.taskUC.CompleteTask(r.Context(), userID, req.TaskID, req.ProofURL, req.ProofText)
+	if err != nil {
+		var restrictedErr *entities.UserRestrictedError
+		if errors.As(err, &restrictedErr) {
+			respondError(w, http.StatusForbidden, err.Error())
+			return
+		}
 		respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	if userTask.Status == entities.UserTaskStatusPending {
+		respondJSON(w, http.StatusAccepted, map[string]interface{}{
+			"message":       "task submitted for verification",
+			"submission_id": userTask.ID,
+			"status":        userTask.Status,
+		})
+		return
+	}
+
 	respondJSON(w, http.StatusOK, map[string]string{
 		"message": "task completed successfully",
 	})
 }
 
-// ListActive returns all active tasks
-// GET /tasks
+// ListActive returns all active tasks that haven't expired in the language
+// selected by Accept-Language
+// GET /tasks?category=&tag=
 func (h *TaskHandler) ListActive(w http.ResponseWriter, r *http.Request) {
-	tasks, err := h.taskUC.GetAvailableTasks(r.Context())
+	filter, locales := parseTaskListQuery(w, r)
+
+	tasks, err := h.taskUC.GetAvailableTasks(r.Context(), filter, locales)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to fetch tasks")
 		return
@@ -73,3 +95,124 @@ func (h *TaskHandler) ListActive(w http.ResponseWriter, r *http.Request) {
 
 	respondJSON(w, http.StatusOK, tasks)
 }
+
+// ListUserTasks returns tasks available to the user and/or the user's completions
+// with earned rewards in the language selected by Accept-Language
+// GET /users/{id}/tasks?status=available|completed|all&category=&tag=
+func (h *TaskHandler) ListUserTasks(w http.ResponseWriter, r *http.Request) {
+	userIDStr := chi.URLParam(r, "id")
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	// Verify authenticated user matches requested user
+	authUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok || authUserID != userID {
+		respondError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = "all"
+	}
+	if status != "available" && status != "completed" && status != "all" {
+		respondError(w, http.StatusBadRequest, "status must be available, completed or all")
+		return
+	}
+
+	filter, locales := parseTaskListQuery(w, r)
+	response := make(map[string]interface{})
+
+	if status == "available" || status == "all" {
+		tasks, err := h.taskUC.GetAvailableTasksForUser(r.Context(), userID, filter, locales)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to fetch tasks")
+			return
+		}
+		if tasks == nil {
+			tasks = []*entities.Task{}
+		}
+		response["available"] = tasks
+	}
+
+	if status == "completed" || status == "all" {
+		completed, err := h.taskUC.GetUserTasks(r.Context(), userID, locales)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to fetch completed tasks")
+			return
+		}
+		if completed == nil {
+			completed = []*entities.UserTaskWithDetails{}
+		}
+		response["completed"] = completed
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+// ListUserQuests returns quest chains with the user's progress
+// GET /users/{id}/quests
+func (h *TaskHandler) ListUserQuests(w http.ResponseWriter, r *http.Request) {
+	userIDStr := chi.URLParam(r, "id")
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	// Verify authenticated user matches requested user
+	authUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok || authUserID != userID {
+		respondError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	quests, err := h.taskUC.GetUserQuests(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to fetch quests")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, quests)
+}
+
+// ListVersions returns the title and reward history of a task
+// GET /admin/tasks/{id}/versions
+func (h *TaskHandler) ListVersions(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+
+	versions, err := h.taskUC.GetTaskVersions(r.Context(), taskID)
+	if err != nil {
+		var notFoundErr *entities.TaskNotFoundError
+		if errors.As(err, &notFoundErr) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to fetch task versions")
+		return
+	}
+	if versions == nil {
+		versions = []*entities.TaskVersion{}
+	}
+
+	respondJSON(w, http.StatusOK, versions)
+}
+
+// parseTaskListQuery returns the category/tag filter and preferred locales of a task list request
+func parseTaskListQuery(w http.ResponseWriter, r *http.Request) (entities.TaskFilter, []string) {
+	// Responses differ by language, keep caches from mixing them up
+	w.Header().Add("Vary", "Accept-Language")
+
+	filter := entities.TaskFilter{
+		Category: r.URL.Query().Get("category"),
+		Tag:      r.URL.Query().Get("tag"),
+	}
+	return filter, entities.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+}
@@ -2,28 +2,37 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
 	"github.com/abdullinmm/user-management-api/internal/middleware"
 	"github.com/abdullinmm/user-management-api/internal/usecase"
 	"github.com/go-chi/chi/v5"
 )
 
+// maxProfilePatchSize limits the size of a profile merge patch
+const maxProfilePatchSize = 64 << 10
+
 // UserHandler handles user-related HTTP requests
 type UserHandler struct {
-	userUC *usecase.UserUseCase
+	userUC   *usecase.UserUseCase
+	streakUC *usecase.StreakUseCase
 }
 
 // NewUserHandler creates a new user handler
-func NewUserHandler(userUC *usecase.UserUseCase) *UserHandler {
+func NewUserHandler(userUC *usecase.UserUseCase, streakUC *usecase.StreakUseCase) *UserHandler {
 	return &UserHandler{
-		userUC: userUC,
+		userUC:   userUC,
+		streakUC: streakUC,
 	}
 }
 
 // GetStatus returns user status with balance and completed tasks
-// GET /users/{id}/status
+// GET /users/{id}/status?currency=points
 func (h *UserHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
 	userIDStr := chi.URLParam(r, "id")
 	userID, err := strconv.ParseInt(userIDStr, 10, 64)
@@ -39,21 +48,158 @@ func (h *UserHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.userUC.GetByID(r.Context(), userID)
+	currency := r.URL.Query().Get("currency")
+	if currency == "" {
+		currency = entities.DefaultCurrency
+	}
+
+	user, err := h.userUC.GetByID(r.Context(), userID, currency)
 	if err != nil {
+		var currencyErr *entities.CurrencyNotFoundError
+		if errors.As(err, &currencyErr) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		respondError(w, http.StatusNotFound, "user not found")
 		return
 	}
 
+	level, err := h.userUC.GetLevel(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to fetch level")
+		return
+	}
+
+	streaks, err := h.streakUC.GetSummary(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to fetch streaks")
+		return
+	}
+
 	respondJSON(w, http.StatusOK, map[string]interface{}{
 		"id":          user.ID,
 		"username":    user.Username,
 		"referrer_id": user.ReferrerID,
 		"balance":     user.Balance,
+		"currency":    currency,
+		"balances":    user.Balances,
+		"level":       level,
+		"streaks":     streaks,
 		"created_at":  user.CreatedAt,
 	})
 }
 
+// GetProfile returns the user with profile and balances
+// GET /users/{id}
+func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorizedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.userUC.GetByID(r.Context(), userID, entities.DefaultCurrency)
+	if err != nil {
+		var notFoundErr *entities.UserNotFoundError
+		if errors.As(err, &notFoundErr) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to get user")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, user)
+}
+
+// UpdateProfile applies a JSON merge patch (RFC 7396) to the user's username and profile
+// PATCH /users/{id}
+func (h *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorizedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "application/merge-patch+json") && !strings.HasPrefix(contentType, "application/json") {
+		respondError(w, http.StatusUnsupportedMediaType, "content type must be application/merge-patch+json")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxProfilePatchSize))
+	if err != nil {
+		respondError(w, http.StatusRequestEntityTooLarge, "request body too large")
+		return
+	}
+
+	patch, err := entities.ParseProfilePatch(body)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := h.userUC.UpdateProfile(r.Context(), userID, patch)
+	if err != nil {
+		var invalidErr *entities.InvalidProfileError
+		var usernameErr *entities.InvalidUsernameError
+		var takenErr *entities.ProfileFieldTakenError
+		var notFoundErr *entities.UserNotFoundError
+		switch {
+		case errors.As(err, &usernameErr):
+			respondUsernameError(w, http.StatusUnprocessableEntity, usernameErr)
+		case errors.As(err, &invalidErr):
+			respondError(w, http.StatusUnprocessableEntity, err.Error())
+		case errors.As(err, &takenErr):
+			respondError(w, http.StatusConflict, err.Error())
+		case errors.As(err, &notFoundErr):
+			respondError(w, http.StatusNotFound, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, "failed to update profile")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, user)
+}
+
+// GetUsernameHistory returns previous usernames of the user
+// GET /users/{id}/username-history
+func (h *UserHandler) GetUsernameHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorizedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	changes, err := h.userUC.GetUsernameHistory(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to fetch username history")
+		return
+	}
+	if changes == nil {
+		changes = []*entities.UsernameChange{}
+	}
+
+	respondJSON(w, http.StatusOK, changes)
+}
+
+// authorizedUserID parses the user ID from the URL and checks that it is the
+// authenticated user, responding with an error otherwise
+func (h *UserHandler) authorizedUserID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid user ID")
+		return 0, false
+	}
+
+	// Verify authenticated user matches requested user
+	authUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok || authUserID != userID {
+		respondError(w, http.StatusForbidden, "access denied")
+		return 0, false
+	}
+
+	return userID, true
+}
+
 // SetReferrer sets the referrer for a user
 // POST /users/{id}/referrer
 func (h *UserHandler) SetReferrer(w http.ResponseWriter, r *http.Request) {
@@ -112,7 +258,16 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.userUC.Create(r.Context(), req.Username)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		var usernameErr *entities.InvalidUsernameError
+		var takenErr *entities.ProfileFieldTakenError
+		switch {
+		case errors.As(err, &usernameErr):
+			respondUsernameError(w, http.StatusBadRequest, usernameErr)
+		case errors.As(err, &takenErr):
+			respondError(w, http.StatusConflict, err.Error())
+		default:
+			respondError(w, http.StatusBadRequest, err.Error())
+		}
 		return
 	}
 
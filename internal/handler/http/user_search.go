@@ -0,0 +1,132 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+)
+
+// defaultUserSearchLimit is the page size of a user search without limit
+const defaultUserSearchLimit = 50
+
+// Search finds users by username and filters, sorted and paged by cursor
+// GET /admin/users?q=&match=prefix|substring&created_from=&created_to=&has_referrer=&min_balance=&max_balance=&currency=&status=&sort=&order=&limit=&cursor=
+func (h *UserHandler) Search(w http.ResponseWriter, r *http.Request) {
+	search, err := parseUserSearch(r.URL.Query())
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.userUC.SearchUsers(r.Context(), search)
+	if err != nil {
+		var searchErr *entities.InvalidUserSearchError
+		var currencyErr *entities.CurrencyNotFoundError
+		switch {
+		case errors.As(err, &searchErr), errors.As(err, &currencyErr):
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, "failed to search users")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, page)
+}
+
+// parseUserSearch builds a user search from query parameters, newest users first by default
+func parseUserSearch(query url.Values) (*entities.UserSearch, error) {
+	search := &entities.UserSearch{
+		Limit:      defaultUserSearchLimit,
+		Query:      query.Get("q"),
+		Match:      entities.UserMatchPrefix,
+		Currency:   query.Get("currency"),
+		Status:     query.Get("status"),
+		Sort:       entities.UserSortCreatedAt,
+		Descending: true,
+	}
+
+	if match := query.Get("match"); match != "" {
+		search.Match = match
+	}
+	if sort := query.Get("sort"); sort != "" {
+		search.Sort = sort
+	}
+	switch query.Get("order") {
+	case "":
+	case "asc":
+		search.Descending = false
+	case "desc":
+		search.Descending = true
+	default:
+		return nil, &entities.InvalidUserSearchError{Reason: "order must be asc or desc"}
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, &entities.InvalidUserSearchError{Reason: "invalid limit"}
+		}
+		search.Limit = limit
+	}
+
+	var err error
+	if search.CreatedFrom, err = parseSearchTime(query, "created_from"); err != nil {
+		return nil, err
+	}
+	if search.CreatedTo, err = parseSearchTime(query, "created_to"); err != nil {
+		return nil, err
+	}
+	if search.MinBalance, err = parseSearchInt(query, "min_balance"); err != nil {
+		return nil, err
+	}
+	if search.MaxBalance, err = parseSearchInt(query, "max_balance"); err != nil {
+		return nil, err
+	}
+
+	if v := query.Get("has_referrer"); v != "" {
+		hasReferrer, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, &entities.InvalidUserSearchError{Reason: "has_referrer must be true or false"}
+		}
+		search.HasReferrer = &hasReferrer
+	}
+
+	if v := query.Get("cursor"); v != "" {
+		if search.After, err = entities.DecodeUserSearchCursor(v); err != nil {
+			return nil, err
+		}
+	}
+
+	return search, nil
+}
+
+// parseSearchTime parses an optional RFC 3339 time parameter
+func parseSearchTime(query url.Values, name string) (*time.Time, error) {
+	v := query.Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, &entities.InvalidUserSearchError{Reason: name + " must be an RFC 3339 time"}
+	}
+	return &t, nil
+}
+
+// parseSearchInt parses an optional integer parameter
+func parseSearchInt(query url.Values, name string) (*int64, error) {
+	v := query.Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	i, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil, &entities.InvalidUserSearchError{Reason: name + " must be an integer"}
+	}
+	return &i, nil
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"strings"
 
@@ -15,8 +16,11 @@ const (
 	UserIDKey contextKey = "user_id"
 )
 
-// Auth creates JWT authentication middleware
-func Auth(jwtManager *jwtpkg.Manager) func(http.Handler) http.Handler {
+// StatusLookup returns why a user can't use the API, empty if the user isn't restricted
+type StatusLookup func(ctx context.Context, userID int64) (string, error)
+
+// Auth creates JWT authentication middleware rejecting suspended and banned users
+func Auth(jwtManager *jwtpkg.Manager, lookup StatusLookup) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Get Authorization header
@@ -40,6 +44,18 @@ func Auth(jwtManager *jwtpkg.Manager) func(http.Handler) http.Handler {
 				return
 			}
 
+			// Reject suspended and banned users
+			denied, err := lookup(r.Context(), claims.UserID)
+			if err != nil {
+				http.Error(w, `{"error":"failed to check account status"}`, http.StatusInternalServerError)
+				return
+			}
+			if denied != "" {
+				body, _ := json.Marshal(map[string]string{"error": denied})
+				http.Error(w, string(body), http.StatusForbidden)
+				return
+			}
+
 			// Add user ID to context
 			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
 			next.ServeHTTP(w, r.WithContext(ctx))
@@ -52,3 +68,35 @@ func GetUserIDFromContext(ctx context.Context) (int64, bool) {
 	userID, ok := ctx.Value(UserIDKey).(int64)
 	return userID, ok
 }
+
+// RoleLookup returns the role of a user, empty if the user doesn't exist
+type RoleLookup func(ctx context.Context, userID int64) (string, error)
+
+// RequireRole creates middleware that allows only users with one of the given roles.
+// It must be applied after Auth
+func RequireRole(lookup RoleLookup, roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := GetUserIDFromContext(r.Context())
+			if !ok {
+				http.Error(w, `{"error":"access denied"}`, http.StatusForbidden)
+				return
+			}
+
+			role, err := lookup(r.Context(), userID)
+			if err != nil {
+				http.Error(w, `{"error":"failed to check permissions"}`, http.StatusInternalServerError)
+				return
+			}
+
+			for _, allowed := range roles {
+				if role == allowed {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			http.Error(w, `{"error":"access denied"}`, http.StatusForbidden)
+		})
+	}
+}
@@ -2,7 +2,12 @@ package middleware
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	jwtpkg "github.com/abdullinmm/user-management-api/internal/pkg/jwt"
 )
 
 func TestGetUserIDFromContext_WithUserID(t *testing.T) {
@@ -82,3 +87,105 @@ func TestGetUserIDFromContext_WithNegativeValue(t *testing.T) {
 		t.Errorf("Expected user ID -1, got %d", retrievedID)
 	}
 }
+
+func TestRequireRole(t *testing.T) {
+	roles := map[int64]string{1: "user", 2: "moderator", 3: "admin"}
+	lookup := func(ctx context.Context, userID int64) (string, error) {
+		return roles[userID], nil
+	}
+
+	handler := RequireRole(lookup, "moderator", "admin")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		status int
+	}{
+		{"regular user", context.WithValue(context.Background(), UserIDKey, int64(1)), http.StatusForbidden},
+		{"moderator", context.WithValue(context.Background(), UserIDKey, int64(2)), http.StatusOK},
+		{"admin", context.WithValue(context.Background(), UserIDKey, int64(3)), http.StatusOK},
+		{"unknown user", context.WithValue(context.Background(), UserIDKey, int64(4)), http.StatusForbidden},
+		{"unauthenticated", context.Background(), http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(tt.ctx)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, rec.Code)
+			}
+		})
+	}
+}
+
+func TestRequireRole_LookupError(t *testing.T) {
+	lookup := func(ctx context.Context, userID int64) (string, error) {
+		return "", errors.New("database unavailable")
+	}
+
+	handler := RequireRole(lookup, "admin")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Handler must not be called when role lookup fails")
+	}))
+
+	ctx := context.WithValue(context.Background(), UserIDKey, int64(1))
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+}
+
+func TestAuth_StatusLookup(t *testing.T) {
+	jwtManager := jwtpkg.NewManager("test_secret")
+	lookup := func(ctx context.Context, userID int64) (string, error) {
+		switch userID {
+		case 2:
+			return "user 2 is banned: spam", nil
+		case 3:
+			return "", errors.New("database unavailable")
+		case 4:
+			return "user with id 4 not found", nil
+		}
+		return "", nil
+	}
+
+	handler := Auth(jwtManager, lookup)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		userID int64
+		status int
+	}{
+		{"active user", 1, http.StatusOK},
+		{"banned user", 2, http.StatusForbidden},
+		{"lookup error", 3, http.StatusInternalServerError},
+		{"missing user", 4, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := jwtManager.GenerateToken(tt.userID)
+			if err != nil {
+				t.Fatalf("Failed to generate token: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package pubsub
+
+import "sync"
+
+// Broker fans out published messages to all current subscribers.
+// Publishing never blocks: messages are dropped for subscribers whose buffer is full.
+type Broker[T any] struct {
+	mu          sync.RWMutex
+	subscribers map[chan T]struct{}
+}
+
+// NewBroker creates a new broker
+func NewBroker[T any]() *Broker[T] {
+	return &Broker[T]{
+		subscribers: make(map[chan T]struct{}),
+	}
+}
+
+// Subscribe registers a subscriber with the given buffer size and returns its
+// channel together with a function that unsubscribes and closes the channel
+func (b *Broker[T]) Subscribe(buffer int) (<-chan T, func()) {
+	ch := make(chan T, buffer)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish delivers the message to every subscriber with free buffer space
+func (b *Broker[T]) Publish(msg T) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package pubsub
+
+import "testing"
+
+func TestBroker_PublishToSubscribers(t *testing.T) {
+	b := NewBroker[int]()
+	first, unsubscribeFirst := b.Subscribe(1)
+	second, unsubscribeSecond := b.Subscribe(1)
+	defer unsubscribeFirst()
+	defer unsubscribeSecond()
+
+	b.Publish(42)
+
+	if got := <-first; got != 42 {
+		t.Errorf("Expected 42 for first subscriber, got %d", got)
+	}
+	if got := <-second; got != 42 {
+		t.Errorf("Expected 42 for second subscriber, got %d", got)
+	}
+}
+
+func TestBroker_DropsWhenBufferFull(t *testing.T) {
+	b := NewBroker[int]()
+	ch, unsubscribe := b.Subscribe(1)
+	defer unsubscribe()
+
+	b.Publish(1)
+	b.Publish(2) // dropped, must not block
+
+	if got := <-ch; got != 1 {
+		t.Errorf("Expected 1, got %d", got)
+	}
+	if len(ch) != 0 {
+		t.Errorf("Expected dropped message, got %d buffered", len(ch))
+	}
+}
+
+func TestBroker_Unsubscribe(t *testing.T) {
+	b := NewBroker[int]()
+	ch, unsubscribe := b.Subscribe(1)
+
+	unsubscribe()
+	unsubscribe() // safe to call twice
+
+	if _, ok := <-ch; ok {
+		t.Error("Expected channel to be closed after unsubscribe")
+	}
+
+	b.Publish(1) // must not panic on closed channel
+}
@@ -0,0 +1,31 @@
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Prefix is the optional algorithm prefix of a signature ("sha256=<hex>")
+const Prefix = "sha256="
+
+// Sign returns the hex encoded HMAC-SHA256 of payload
+func Sign(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is a valid HMAC-SHA256 of payload, comparing in constant time.
+// The signature may carry the "sha256=" prefix
+func Verify(secret, payload []byte, signature string) bool {
+	expected, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), Prefix))
+	if err != nil || len(expected) != sha256.Size {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -0,0 +1,42 @@
+package signature
+
+import "testing"
+
+func TestSign_KnownVector(t *testing.T) {
+	// RFC 4231 test case 2
+	got := Sign([]byte("Jefe"), []byte("what do ya want for nothing?"))
+	want := "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	secret := []byte("partner-secret")
+	payload := []byte(`{"user_id":1,"task_code":"TASK_PARTNER"}`)
+	sig := Sign(secret, payload)
+
+	tests := []struct {
+		name      string
+		secret    []byte
+		payload   []byte
+		signature string
+		want      bool
+	}{
+		{"valid", secret, payload, sig, true},
+		{"valid with prefix", secret, payload, Prefix + sig, true},
+		{"wrong secret", []byte("other"), payload, sig, false},
+		{"tampered payload", secret, []byte(`{"user_id":2,"task_code":"TASK_PARTNER"}`), sig, false},
+		{"not hex", secret, payload, "not-a-signature", false},
+		{"truncated", secret, payload, sig[:32], false},
+		{"empty", secret, payload, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify(tt.secret, tt.payload, tt.signature); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package skiplist
+
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	maxLevel    = 32
+	probability = 0.25
+)
+
+// Entry is an element of the list ordered by Score descending, then ID ascending
+type Entry[V any] struct {
+	ID    int64
+	Score int64
+	Value V
+}
+
+type node[V any] struct {
+	entry Entry[V]
+	next  []*node[V]
+	// span[i] is the number of positions between this node and next[i]
+	span []int
+}
+
+// List is an indexable skip list keyed by ID that supports O(log n)
+// updates, position lookups and range queries by position
+type List[V any] struct {
+	head   *node[V]
+	nodes  map[int64]*node[V]
+	rnd    *rand.Rand
+	level  int
+	length int
+}
+
+// New creates an empty list
+func New[V any]() *List[V] {
+	return &List[V]{
+		head: &node[V]{
+			next: make([]*node[V], maxLevel),
+			span: make([]int, maxLevel),
+		},
+		nodes: make(map[int64]*node[V]),
+		rnd:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		level: 1,
+	}
+}
+
+// Len returns the number of entries
+func (l *List[V]) Len() int {
+	return l.length
+}
+
+// Get returns the entry with the given ID
+func (l *List[V]) Get(id int64) (Entry[V], bool) {
+	n, ok := l.nodes[id]
+	if !ok {
+		return Entry[V]{}, false
+	}
+	return n.entry, true
+}
+
+// Set inserts the entry or replaces the existing entry with the same ID
+func (l *List[V]) Set(id, score int64, value V) {
+	if n, ok := l.nodes[id]; ok {
+		l.remove(n)
+	}
+	l.insert(Entry[V]{ID: id, Score: score, Value: value})
+}
+
+// Delete removes the entry with the given ID and reports whether it existed
+func (l *List[V]) Delete(id int64) bool {
+	n, ok := l.nodes[id]
+	if !ok {
+		return false
+	}
+	l.remove(n)
+	return true
+}
+
+// Position returns the 1-based position of the entry with the given ID
+func (l *List[V]) Position(id int64) (int, bool) {
+	n, ok := l.nodes[id]
+	if !ok {
+		return 0, false
+	}
+
+	position := 0
+	x := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && !before(n.entry, x.next[i].entry) {
+			position += x.span[i]
+			x = x.next[i]
+		}
+		if x == n {
+			return position, true
+		}
+	}
+	return 0, false
+}
+
+// CountAbove returns the number of entries with a score strictly greater than score
+func (l *List[V]) CountAbove(score int64) int {
+	count := 0
+	x := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && x.next[i].entry.Score > score {
+			count += x.span[i]
+			x = x.next[i]
+		}
+	}
+	return count
+}
+
+// Range returns up to limit entries starting after offset positions
+func (l *List[V]) Range(offset, limit int) []Entry[V] {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 || offset >= l.length {
+		return nil
+	}
+
+	// Find the node just before the first requested position
+	traversed := 0
+	x := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && traversed+x.span[i] <= offset {
+			traversed += x.span[i]
+			x = x.next[i]
+		}
+	}
+
+	entries := make([]Entry[V], 0, min(limit, l.length-offset))
+	for x = x.next[0]; x != nil && len(entries) < limit; x = x.next[0] {
+		entries = append(entries, x.entry)
+	}
+	return entries
+}
+
+func (l *List[V]) insert(e Entry[V]) {
+	var update [maxLevel]*node[V]
+	var rank [maxLevel]int
+
+	x := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		if i < l.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.next[i] != nil && before(x.next[i].entry, e) {
+			rank[i] += x.span[i]
+			x = x.next[i]
+		}
+		update[i] = x
+	}
+
+	level := l.randomLevel()
+	if level > l.level {
+		for i := l.level; i < level; i++ {
+			rank[i] = 0
+			update[i] = l.head
+			update[i].span[i] = l.length
+		}
+		l.level = level
+	}
+
+	n := &node[V]{
+		entry: e,
+		next:  make([]*node[V], level),
+		span:  make([]int, level),
+	}
+	for i := 0; i < level; i++ {
+		n.next[i] = update[i].next[i]
+		update[i].next[i] = n
+		n.span[i] = update[i].span[i] - (rank[0] - rank[i])
+		update[i].span[i] = rank[0] - rank[i] + 1
+	}
+	for i := level; i < l.level; i++ {
+		update[i].span[i]++
+	}
+
+	l.length++
+	l.nodes[e.ID] = n
+}
+
+func (l *List[V]) remove(n *node[V]) {
+	var update [maxLevel]*node[V]
+
+	x := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && before(x.next[i].entry, n.entry) {
+			x = x.next[i]
+		}
+		update[i] = x
+	}
+
+	for i := 0; i < l.level; i++ {
+		if update[i].next[i] == n {
+			update[i].span[i] += n.span[i] - 1
+			update[i].next[i] = n.next[i]
+		} else {
+			update[i].span[i]--
+		}
+	}
+	for l.level > 1 && l.head.next[l.level-1] == nil {
+		l.level--
+	}
+
+	l.length--
+	delete(l.nodes, n.entry.ID)
+}
+
+func (l *List[V]) randomLevel() int {
+	level := 1
+	for level < maxLevel && l.rnd.Float64() < probability {
+		level++
+	}
+	return level
+}
+
+// before reports whether a is ordered before b
+func before[V any](a, b Entry[V]) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	return a.ID < b.ID
+}
@@ -0,0 +1,117 @@
+package skiplist
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestList_OrderAndPosition(t *testing.T) {
+	l := New[string]()
+	l.Set(1, 100, "alice")
+	l.Set(2, 300, "bob")
+	l.Set(3, 200, "carol")
+	l.Set(4, 200, "dave")
+
+	entries := l.Range(0, 10)
+	want := []int64{2, 3, 4, 1}
+	if len(entries) != len(want) {
+		t.Fatalf("Expected %d entries, got %d", len(want), len(entries))
+	}
+	for i, id := range want {
+		if entries[i].ID != id {
+			t.Errorf("Expected ID %d at position %d, got %d", id, i+1, entries[i].ID)
+		}
+	}
+
+	if pos, ok := l.Position(4); !ok || pos != 3 {
+		t.Errorf("Expected position 3 for ID 4, got %d", pos)
+	}
+
+	if count := l.CountAbove(200); count != 1 {
+		t.Errorf("Expected 1 entry above 200, got %d", count)
+	}
+}
+
+func TestList_UpdateAndDelete(t *testing.T) {
+	l := New[string]()
+	l.Set(1, 100, "alice")
+	l.Set(2, 50, "bob")
+
+	l.Set(2, 150, "bob")
+	if pos, _ := l.Position(2); pos != 1 {
+		t.Errorf("Expected updated ID 2 at position 1, got %d", pos)
+	}
+
+	if l.Len() != 2 {
+		t.Errorf("Expected length 2 after update, got %d", l.Len())
+	}
+
+	if !l.Delete(2) {
+		t.Fatal("Expected ID 2 to be deleted")
+	}
+
+	if _, ok := l.Get(2); ok {
+		t.Error("Expected ID 2 to be absent after delete")
+	}
+
+	if pos, _ := l.Position(1); pos != 1 {
+		t.Errorf("Expected ID 1 at position 1 after delete, got %d", pos)
+	}
+}
+
+func TestList_MatchesSortedSlice(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	l := New[struct{}]()
+	scores := make(map[int64]int64)
+
+	for i := 0; i < 5000; i++ {
+		id := int64(rnd.Intn(500))
+		if rnd.Intn(5) == 0 {
+			l.Delete(id)
+			delete(scores, id)
+			continue
+		}
+		score := int64(rnd.Intn(100))
+		l.Set(id, score, struct{}{})
+		scores[id] = score
+	}
+
+	ids := make([]int64, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+
+	if l.Len() != len(ids) {
+		t.Fatalf("Expected length %d, got %d", len(ids), l.Len())
+	}
+
+	for i, id := range ids {
+		if pos, ok := l.Position(id); !ok || pos != i+1 {
+			t.Fatalf("Expected ID %d at position %d, got %d", id, i+1, pos)
+		}
+	}
+
+	page := l.Range(100, 25)
+	for i, entry := range page {
+		if entry.ID != ids[100+i] {
+			t.Fatalf("Expected ID %d at offset %d, got %d", ids[100+i], 100+i, entry.ID)
+		}
+	}
+
+	above := 0
+	for _, id := range ids {
+		if scores[id] > 50 {
+			above++
+		}
+	}
+	if count := l.CountAbove(50); count != above {
+		t.Errorf("Expected %d entries above 50, got %d", above, count)
+	}
+}
@@ -0,0 +1,264 @@
+package cache
+
+import (
+	"context"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+	"github.com/abdullinmm/user-management-api/internal/pkg/skiplist"
+)
+
+// board is an in-memory leaderboard of a single currency
+type board struct {
+	mu    sync.RWMutex
+	list  *skiplist.List[entities.LeaderboardEntry]
+	stale bool
+}
+
+// LeaderboardCache wraps a BalanceRepository and keeps per-currency leaderboards
+// in memory, serving top-N, rank and neighbour queries in O(log n). Boards are
+// seeded from the database on first use, updated on every balance change and
+// periodically resynced; queries fall back to the database when a board cannot
+// answer them (dense ranking, unknown users or seeding errors).
+type LeaderboardCache struct {
+	balanceRepo interfaces.BalanceRepository
+	mu          sync.Mutex
+	boards      map[string]*board
+}
+
+// NewLeaderboardCache creates a new leaderboard cache over the balance repository
+func NewLeaderboardCache(balanceRepo interfaces.BalanceRepository) *LeaderboardCache {
+	return &LeaderboardCache{
+		balanceRepo: balanceRepo,
+		boards:      make(map[string]*board),
+	}
+}
+
+// Run periodically resyncs all loaded boards with the database until ctx is done
+func (c *LeaderboardCache) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			currencies := make([]string, 0, len(c.boards))
+			for currency := range c.boards {
+				currencies = append(currencies, currency)
+			}
+			c.mu.Unlock()
+
+			for _, currency := range currencies {
+				if _, err := c.seed(ctx, currency); err != nil {
+					log.Printf("failed to resync %s leaderboard: %v", currency, err)
+				}
+			}
+		}
+	}
+}
+
+// GetByUserID retrieves a user's balance in the given currency
+func (c *LeaderboardCache) GetByUserID(ctx context.Context, userID int64, currency string) (*entities.Balance, error) {
+	return c.balanceRepo.GetByUserID(ctx, userID, currency)
+}
+
+// GetAllByUserID retrieves a user's balances in all currencies
+func (c *LeaderboardCache) GetAllByUserID(ctx context.Context, userID int64) ([]*entities.Balance, error) {
+	return c.balanceRepo.GetAllByUserID(ctx, userID)
+}
+
+// GetEntry retrieves a user's unranked leaderboard entry from the database
+func (c *LeaderboardCache) GetEntry(ctx context.Context, userID int64, currency string) (*entities.LeaderboardEntry, error) {
+	return c.balanceRepo.GetEntry(ctx, userID, currency)
+}
+
+// UpdatePoints updates the balance in the database and then the in-memory board
+func (c *LeaderboardCache) UpdatePoints(ctx context.Context, userID int64, currency string, delta int64) error {
+	if err := c.balanceRepo.UpdatePoints(ctx, userID, currency, delta); err != nil {
+		return err
+	}
+
+	c.Refresh(ctx, userID, currency)
+	return nil
+}
+
+// Refresh reloads a single user's entry of a loaded board from the database
+func (c *LeaderboardCache) Refresh(ctx context.Context, userID int64, currency string) {
+	c.mu.Lock()
+	b := c.boards[currency]
+	c.mu.Unlock()
+	if b == nil {
+		return
+	}
+
+	// Ranks are derived from the board, only the user's own row is loaded
+	entry, err := c.balanceRepo.GetEntry(ctx, userID, currency)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case err != nil:
+		// Let the next read reseed the board from the database
+		b.stale = true
+	case entry == nil:
+		b.list.Delete(userID)
+	default:
+		b.list.Set(entry.UserID, entry.Points, *entry)
+	}
+}
+
+// Invalidate marks a loaded board stale after bulk balance changes, the next
+// read reseeds it from the database
+func (c *LeaderboardCache) Invalidate(currency string) {
+	c.mu.Lock()
+	b := c.boards[currency]
+	c.mu.Unlock()
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	b.stale = true
+	b.mu.Unlock()
+}
+
+// GetLeaderboard retrieves top users from memory, or from the database for dense ranking
+func (c *LeaderboardCache) GetLeaderboard(ctx context.Context, currency string, mode entities.RankingMode, limit, offset int) ([]*entities.LeaderboardEntry, error) {
+	if mode != entities.RankingCompetition {
+		return c.balanceRepo.GetLeaderboard(ctx, currency, mode, limit, offset)
+	}
+
+	b, err := c.board(ctx, currency)
+	if err != nil {
+		return c.balanceRepo.GetLeaderboard(ctx, currency, mode, limit, offset)
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return rankedEntries(b.list, b.list.Range(offset, limit)), nil
+}
+
+// GetRank retrieves a user's rank and percentile from memory, falling back to the database
+func (c *LeaderboardCache) GetRank(ctx context.Context, userID int64, currency string, mode entities.RankingMode) (*entities.UserRank, error) {
+	if mode != entities.RankingCompetition {
+		return c.balanceRepo.GetRank(ctx, userID, currency, mode)
+	}
+
+	b, err := c.board(ctx, currency)
+	if err != nil {
+		return c.balanceRepo.GetRank(ctx, userID, currency, mode)
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	entry, ok := b.list.Get(userID)
+	if !ok {
+		return c.balanceRepo.GetRank(ctx, userID, currency, mode)
+	}
+
+	total := int64(b.list.Len())
+	rank := &entities.UserRank{
+		UserID:   userID,
+		Points:   entry.Score,
+		Rank:     int64(b.list.CountAbove(entry.Score)) + 1,
+		Total:    total,
+		Currency: currency,
+	}
+
+	// Same definition as PERCENT_RANK: share of other users with fewer points
+	if total > 1 {
+		lower := total - int64(b.list.CountAbove(entry.Score-1))
+		rank.Percentile = float64(lower) / float64(total-1) * 100
+	}
+
+	return rank, nil
+}
+
+// GetNeighbours retrieves the user's entry with n entries above and below it from memory
+func (c *LeaderboardCache) GetNeighbours(ctx context.Context, userID int64, currency string, mode entities.RankingMode, n int) ([]*entities.LeaderboardEntry, error) {
+	if mode != entities.RankingCompetition {
+		return c.balanceRepo.GetNeighbours(ctx, userID, currency, mode, n)
+	}
+
+	b, err := c.board(ctx, currency)
+	if err != nil {
+		return c.balanceRepo.GetNeighbours(ctx, userID, currency, mode, n)
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	position, ok := b.list.Position(userID)
+	if !ok {
+		return c.balanceRepo.GetNeighbours(ctx, userID, currency, mode, n)
+	}
+
+	offset := max(position-1-n, 0)
+	return rankedEntries(b.list, b.list.Range(offset, position+n-offset)), nil
+}
+
+// board returns the loaded board of the currency, seeding it from the database if needed
+func (c *LeaderboardCache) board(ctx context.Context, currency string) (*board, error) {
+	c.mu.Lock()
+	b := c.boards[currency]
+	c.mu.Unlock()
+
+	if b != nil {
+		b.mu.RLock()
+		stale := b.stale
+		b.mu.RUnlock()
+		if !stale {
+			return b, nil
+		}
+	}
+
+	return c.seed(ctx, currency)
+}
+
+// seed loads the full leaderboard of the currency from the database and swaps it in
+func (c *LeaderboardCache) seed(ctx context.Context, currency string) (*board, error) {
+	entries, err := c.balanceRepo.GetLeaderboard(ctx, currency, entities.RankingCompetition, math.MaxInt32, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	list := skiplist.New[entities.LeaderboardEntry]()
+	for _, entry := range entries {
+		list.Set(entry.UserID, entry.Points, *entry)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b := c.boards[currency]
+	if b == nil {
+		b = &board{}
+		c.boards[currency] = b
+	}
+
+	b.mu.Lock()
+	b.list = list
+	b.stale = false
+	b.mu.Unlock()
+
+	return b, nil
+}
+
+// rankedEntries converts list entries to leaderboard entries with competition ranks
+func rankedEntries(list *skiplist.List[entities.LeaderboardEntry], entries []skiplist.Entry[entities.LeaderboardEntry]) []*entities.LeaderboardEntry {
+	leaderboard := make([]*entities.LeaderboardEntry, 0, len(entries))
+	for _, e := range entries {
+		entry := e.Value
+		entry.Rank = int64(list.CountAbove(e.Score)) + 1
+		leaderboard = append(leaderboard, &entry)
+	}
+	return leaderboard
+}
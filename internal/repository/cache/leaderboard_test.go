@@ -0,0 +1,182 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+)
+
+// stubBalances is an in-memory BalanceRepository of a single currency
+type stubBalances struct {
+	points     map[int64]int64
+	entryErr   error
+	seedCalls  int
+	entryCalls int
+}
+
+func newStubBalances(points map[int64]int64) *stubBalances {
+	return &stubBalances{points: points}
+}
+
+func (s *stubBalances) GetByUserID(ctx context.Context, userID int64, currency string) (*entities.Balance, error) {
+	return nil, nil
+}
+
+func (s *stubBalances) GetAllByUserID(ctx context.Context, userID int64) ([]*entities.Balance, error) {
+	return nil, nil
+}
+
+func (s *stubBalances) UpdatePoints(ctx context.Context, userID int64, currency string, delta int64) error {
+	s.points[userID] += delta
+	return nil
+}
+
+func (s *stubBalances) GetLeaderboard(ctx context.Context, currency string, mode entities.RankingMode, limit, offset int) ([]*entities.LeaderboardEntry, error) {
+	s.seedCalls++
+	var entries []*entities.LeaderboardEntry
+	for userID, points := range s.points {
+		entries = append(entries, &entities.LeaderboardEntry{UserID: userID, Points: points, Currency: currency})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Points != entries[j].Points {
+			return entries[i].Points > entries[j].Points
+		}
+		return entries[i].UserID < entries[j].UserID
+	})
+	return entries, nil
+}
+
+func (s *stubBalances) GetRank(ctx context.Context, userID int64, currency string, mode entities.RankingMode) (*entities.UserRank, error) {
+	return nil, nil
+}
+
+func (s *stubBalances) GetNeighbours(ctx context.Context, userID int64, currency string, mode entities.RankingMode, n int) ([]*entities.LeaderboardEntry, error) {
+	return nil, errors.New("unexpected full ranking query")
+}
+
+func (s *stubBalances) GetEntry(ctx context.Context, userID int64, currency string) (*entities.LeaderboardEntry, error) {
+	s.entryCalls++
+	if s.entryErr != nil {
+		return nil, s.entryErr
+	}
+	points, ok := s.points[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &entities.LeaderboardEntry{UserID: userID, Points: points, Currency: currency}, nil
+}
+
+// leaderboardIDs returns user IDs and ranks of the cached leaderboard
+func leaderboardIDs(t *testing.T, c *LeaderboardCache) ([]int64, []int64) {
+	t.Helper()
+	entries, err := c.GetLeaderboard(context.Background(), entities.DefaultCurrency, entities.RankingCompetition, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to get leaderboard: %v", err)
+	}
+	var ids, ranks []int64
+	for _, entry := range entries {
+		ids = append(ids, entry.UserID)
+		ranks = append(ranks, entry.Rank)
+	}
+	return ids, ranks
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestLeaderboardCache_UpdatePoints(t *testing.T) {
+	ctx := context.Background()
+	repo := newStubBalances(map[int64]int64{1: 100, 2: 200, 3: 300})
+	c := NewLeaderboardCache(repo)
+
+	if ids, _ := leaderboardIDs(t, c); !equalIDs(ids, []int64{3, 2, 1}) {
+		t.Fatalf("Expected seeded order [3 2 1], got %v", ids)
+	}
+
+	if err := c.UpdatePoints(ctx, 1, entities.DefaultCurrency, 200); err != nil {
+		t.Fatalf("Failed to update points: %v", err)
+	}
+	if repo.entryCalls != 1 {
+		t.Errorf("Expected one single-row lookup, got %d", repo.entryCalls)
+	}
+
+	ids, ranks := leaderboardIDs(t, c)
+	if !equalIDs(ids, []int64{1, 3, 2}) || !equalIDs(ranks, []int64{1, 1, 3}) {
+		t.Errorf("Expected order [1 3 2] with ranks [1 1 3], got %v with %v", ids, ranks)
+	}
+
+	rank, err := c.GetRank(ctx, 2, entities.DefaultCurrency, entities.RankingCompetition)
+	if err != nil || rank == nil || rank.Rank != 3 || rank.Total != 3 {
+		t.Errorf("Expected user 2 ranked 3 of 3, got %+v (%v)", rank, err)
+	}
+	if repo.seedCalls != 1 {
+		t.Errorf("Expected the board to be seeded once, got %d", repo.seedCalls)
+	}
+}
+
+func TestLeaderboardCache_RefreshMissingRow(t *testing.T) {
+	ctx := context.Background()
+	repo := newStubBalances(map[int64]int64{1: 100, 2: 200})
+	c := NewLeaderboardCache(repo)
+	leaderboardIDs(t, c)
+
+	// User left the leaderboard, e.g. was banned
+	delete(repo.points, 2)
+	c.Refresh(ctx, 2, entities.DefaultCurrency)
+
+	if ids, _ := leaderboardIDs(t, c); !equalIDs(ids, []int64{1}) {
+		t.Errorf("Expected user 2 removed, got %v", ids)
+	}
+}
+
+func TestLeaderboardCache_StaleReseed(t *testing.T) {
+	ctx := context.Background()
+	repo := newStubBalances(map[int64]int64{1: 100, 2: 200})
+	c := NewLeaderboardCache(repo)
+	leaderboardIDs(t, c)
+
+	// A failed lookup marks the board stale instead of guessing
+	repo.entryErr = errors.New("connection reset")
+	repo.points[1] = 500
+	c.Refresh(ctx, 1, entities.DefaultCurrency)
+	repo.entryErr = nil
+
+	if ids, _ := leaderboardIDs(t, c); !equalIDs(ids, []int64{1, 2}) {
+		t.Errorf("Expected reseeded order [1 2], got %v", ids)
+	}
+	if repo.seedCalls != 2 {
+		t.Errorf("Expected the stale board to be reseeded, got %d seeds", repo.seedCalls)
+	}
+
+	// Bulk changes invalidate the board the same way
+	repo.points[3] = 1000
+	c.Invalidate(entities.DefaultCurrency)
+	if ids, _ := leaderboardIDs(t, c); !equalIDs(ids, []int64{3, 1, 2}) {
+		t.Errorf("Expected reseeded order [3 1 2], got %v", ids)
+	}
+	if repo.seedCalls != 3 {
+		t.Errorf("Expected an invalidated board to be reseeded, got %d seeds", repo.seedCalls)
+	}
+}
+
+func TestLeaderboardCache_RefreshUnloadedBoard(t *testing.T) {
+	repo := newStubBalances(map[int64]int64{1: 100})
+	c := NewLeaderboardCache(repo)
+
+	c.Refresh(context.Background(), 1, entities.DefaultCurrency)
+	if repo.entryCalls != 0 || repo.seedCalls != 0 {
+		t.Errorf("Expected no queries for an unloaded board, got %d lookups and %d seeds", repo.entryCalls, repo.seedCalls)
+	}
+}
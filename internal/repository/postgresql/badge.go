@@ -0,0 +1,105 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// BadgeRepository handles badge-related database operations
+type BadgeRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewBadgeRepository creates a new badge repository
+func NewBadgeRepository(db *pgxpool.Pool) interfaces.BadgeRepository {
+	return &BadgeRepository{db: db}
+}
+
+// GetActive retrieves all active badge definitions
+func (r *BadgeRepository) GetActive(ctx context.Context) ([]*entities.Badge, error) {
+	query := `
+		SELECT id, code, title, description, rule_type, threshold, is_active, created_at
+		FROM badges
+		WHERE is_active = true
+		ORDER BY id ASC`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var badges []*entities.Badge
+	for rows.Next() {
+		var badge entities.Badge
+		err := rows.Scan(
+			&badge.ID,
+			&badge.Code,
+			&badge.Title,
+			&badge.Description,
+			&badge.RuleType,
+			&badge.Threshold,
+			&badge.IsActive,
+			&badge.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		badges = append(badges, &badge)
+	}
+
+	return badges, rows.Err()
+}
+
+// Award records a badge for a user and reports whether it was newly awarded
+func (r *BadgeRepository) Award(ctx context.Context, userID, badgeID int64) (bool, error) {
+	query := `
+		INSERT INTO user_badges (user_id, badge_id, awarded_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP)
+		ON CONFLICT (user_id, badge_id) DO NOTHING`
+
+	result, err := r.db.Exec(ctx, query, userID, badgeID)
+	if err != nil {
+		return false, err
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// GetByUserID retrieves all badges earned by a user with badge details
+func (r *BadgeRepository) GetByUserID(ctx context.Context, userID int64) ([]*entities.UserBadge, error) {
+	query := `
+		SELECT ub.user_id, ub.badge_id, b.code, b.title, b.description, ub.awarded_at
+		FROM user_badges ub
+		JOIN badges b ON ub.badge_id = b.id
+		WHERE ub.user_id = $1
+		ORDER BY ub.awarded_at DESC`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userBadges []*entities.UserBadge
+	for rows.Next() {
+		var ub entities.UserBadge
+		err := rows.Scan(
+			&ub.UserID,
+			&ub.BadgeID,
+			&ub.Code,
+			&ub.Title,
+			&ub.Description,
+			&ub.AwardedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		userBadges = append(userBadges, &ub)
+	}
+
+	return userBadges, rows.Err()
+}
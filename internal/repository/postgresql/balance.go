@@ -19,16 +19,17 @@ func NewBalanceRepository(db *pgxpool.Pool) interfaces.BalanceRepository {
 	return &BalanceRepository{db: db}
 }
 
-// GetByUserID retrieves a user's balance by user ID
-func (r *BalanceRepository) GetByUserID(ctx context.Context, userID int64) (*entities.Balance, error) {
+// GetByUserID retrieves a user's balance in the given currency
+func (r *BalanceRepository) GetByUserID(ctx context.Context, userID int64, currency string) (*entities.Balance, error) {
 	query := `
-			SELECT user_id, points, updated_at
+			SELECT user_id, currency, points, lifetime_points, updated_at
 			FROM balances
-			WHERE user_id = $1`
+			WHERE user_id = $1 AND currency = $2`
 
 	var balance entities.Balance
-	err := r.db.QueryRow(ctx, query, userID).Scan(
-		&balance.UserID, &balance.Points, &balance.UpdatedAt,
+	err := r.db.QueryRow(ctx, query, userID, currency).Scan(
+		&balance.UserID, &balance.Currency, &balance.Points,
+		&balance.LifetimePoints, &balance.UpdatedAt,
 	)
 
 	if err != nil {
@@ -40,8 +41,38 @@ func (r *BalanceRepository) GetByUserID(ctx context.Context, userID int64) (*ent
 	return &balance, nil
 }
 
-// UpdatePoints updates a user's balance by adding delta points
-func (r *BalanceRepository) UpdatePoints(ctx context.Context, userID, delta int64) error {
+// GetAllByUserID retrieves a user's balances in all currencies
+func (r *BalanceRepository) GetAllByUserID(ctx context.Context, userID int64) ([]*entities.Balance, error) {
+	query := `
+			SELECT user_id, currency, points, lifetime_points, updated_at
+			FROM balances
+			WHERE user_id = $1
+			ORDER BY currency ASC`
+
+	rows, err := reader(ctx, r.db).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var balances []*entities.Balance
+	for rows.Next() {
+		var balance entities.Balance
+		err := rows.Scan(
+			&balance.UserID, &balance.Currency, &balance.Points,
+			&balance.LifetimePoints, &balance.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, &balance)
+	}
+
+	return balances, rows.Err()
+}
+
+// UpdatePoints updates a user's balance in the given currency by adding delta points
+func (r *BalanceRepository) UpdatePoints(ctx context.Context, userID int64, currency string, delta int64) error {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return err
@@ -50,13 +81,25 @@ func (r *BalanceRepository) UpdatePoints(ctx context.Context, userID, delta int6
 		_ = tx.Rollback(ctx) // Rollback on error
 	}()
 
-	// Update balance with delta, ensuring points don't go negative
+	// Balances in non-default currencies are created lazily on first update
+	_, err = tx.Exec(ctx, `
+				INSERT INTO balances (user_id, currency, points, updated_at)
+				SELECT id, $2, 0, CURRENT_TIMESTAMP FROM users WHERE id = $1
+				ON CONFLICT (user_id, currency) DO NOTHING`, userID, currency)
+	if err != nil {
+		return err
+	}
+
+	// Update balance with delta, ensuring points don't go negative;
+	// only earned points count towards lifetime points
 	query := `
 				UPDATE balances
-				SET points = points + $2, updated_at = CURRENT_TIMESTAMP
-				WHERE user_id = $1 AND (points + $2) >= 0`
+				SET points = points + $3,
+				    lifetime_points = lifetime_points + GREATEST($3, 0),
+				    updated_at = CURRENT_TIMESTAMP
+				WHERE user_id = $1 AND currency = $2 AND (points + $3) >= 0`
 
-	result, err := tx.Exec(ctx, query, userID, delta)
+	result, err := tx.Exec(ctx, query, userID, currency, delta)
 	if err != nil {
 		return err
 	}
@@ -70,33 +113,124 @@ func (r *BalanceRepository) UpdatePoints(ctx context.Context, userID, delta int6
 	return tx.Commit(ctx)
 }
 
-// GetLeaderboard retrieves top users by balance with pagination
-func (r *BalanceRepository) GetLeaderboard(ctx context.Context, limit, offset int) ([]*entities.UserWithBalance, error) {
-	query := `
-			SELECT b.user_id, u.username, b.points, b.updated_at
-			FROM balances b
-			JOIN users u ON b.user_id = u.id
-			ORDER BY b.points DESC
-			LIMIT $1 OFFSET $2`
+// GetLeaderboard retrieves top users by balance in the given currency with ranks and pagination
+func (r *BalanceRepository) GetLeaderboard(ctx context.Context, currency string, mode entities.RankingMode, limit, offset int) ([]*entities.LeaderboardEntry, error) {
+	query := rankedBalancesQuery(mode) + `
+			SELECT user_id, username, currency, points, updated_at, rank
+			FROM ranked
+			ORDER BY position
+			LIMIT $2 OFFSET $3`
 
-	rows, err := r.db.Query(ctx, query, limit, offset)
+	rows, err := r.db.Query(ctx, query, currency, limit, offset)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var leaderboard []*entities.UserWithBalance
+	return scanLeaderboard(rows)
+}
+
+// GetRank retrieves a user's rank and percentile in the given currency
+func (r *BalanceRepository) GetRank(ctx context.Context, userID int64, currency string, mode entities.RankingMode) (*entities.UserRank, error) {
+	query := rankedBalancesQuery(mode) + `
+			SELECT user_id, currency, points, rank, total, percentile
+			FROM ranked
+			WHERE user_id = $2`
+
+	var rank entities.UserRank
+	err := r.db.QueryRow(ctx, query, currency, userID).Scan(
+		&rank.UserID, &rank.Currency, &rank.Points,
+		&rank.Rank, &rank.Total, &rank.Percentile,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rank, nil
+}
+
+// GetNeighbours retrieves the user's leaderboard entry with n entries above and below it
+func (r *BalanceRepository) GetNeighbours(ctx context.Context, userID int64, currency string, mode entities.RankingMode, n int) ([]*entities.LeaderboardEntry, error) {
+	query := rankedBalancesQuery(mode) + `,
+			me AS (
+				SELECT position FROM ranked WHERE user_id = $2
+			)
+			SELECT ranked.user_id, ranked.username, ranked.currency, ranked.points, ranked.updated_at, ranked.rank
+			FROM ranked, me
+			WHERE ranked.position BETWEEN me.position - $3 AND me.position + $3
+			ORDER BY ranked.position`
+
+	rows, err := r.db.Query(ctx, query, currency, userID, n)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanLeaderboard(rows)
+}
+
+// GetEntry retrieves the user's unranked leaderboard entry, nil if the user has no
+// balance in the currency or is left out of leaderboards
+func (r *BalanceRepository) GetEntry(ctx context.Context, userID int64, currency string) (*entities.LeaderboardEntry, error) {
+	query := `
+		SELECT b.points, u.username, b.updated_at
+		FROM balances b
+		JOIN users u ON b.user_id = u.id
+		WHERE b.user_id = $1 AND b.currency = $2 AND ` + activeUserCondition
+
+	entry := entities.LeaderboardEntry{UserID: userID, Currency: currency}
+	err := r.db.QueryRow(ctx, query, userID, currency).Scan(&entry.Points, &entry.Username, &entry.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// rankedBalancesQuery returns a "ranked" CTE over balances in currency $1 with
+// rank (per mode), unique position, percentile and total count
+func rankedBalancesQuery(mode entities.RankingMode) string {
+	return `
+			WITH ranked AS (
+				SELECT b.user_id, u.username, b.currency, b.points, b.updated_at,
+				       ` + rankFunction(mode) + ` OVER (ORDER BY b.points DESC) AS rank,
+				       ROW_NUMBER() OVER (ORDER BY b.points DESC, b.user_id ASC) AS position,
+				       PERCENT_RANK() OVER (ORDER BY b.points ASC) * 100 AS percentile,
+				       COUNT(*) OVER () AS total
+				FROM balances b
+				JOIN users u ON b.user_id = u.id
+				WHERE b.currency = $1 AND ` + activeUserCondition + `
+			)`
+}
+
+// activeUserCondition matches users "u" that are not deleted, suspended or banned,
+// restricted users are left out of leaderboards
+const activeUserCondition = `u.deleted_at IS NULL AND (u.status = 'active' OR u.status_until <= now())`
+
+// rankFunction returns the SQL window function implementing the ranking mode
+func rankFunction(mode entities.RankingMode) string {
+	if mode == entities.RankingDense {
+		return "DENSE_RANK()"
+	}
+	return "RANK()"
+}
+
+// scanLeaderboard scans rows of user_id, username, currency, points, updated_at, rank
+func scanLeaderboard(rows pgx.Rows) ([]*entities.LeaderboardEntry, error) {
+	var leaderboard []*entities.LeaderboardEntry
 	for rows.Next() {
-		var entry entities.UserWithBalance
+		var entry entities.LeaderboardEntry
 		err := rows.Scan(
-			&entry.UserID, &entry.Username,
-			&entry.Points, &entry.UpdatedAt,
+			&entry.UserID, &entry.Username, &entry.Currency,
+			&entry.Points, &entry.UpdatedAt, &entry.Rank,
 		)
-
 		if err != nil {
 			return nil, err
 		}
-
 		leaderboard = append(leaderboard, &entry)
 	}
 
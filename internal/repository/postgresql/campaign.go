@@ -0,0 +1,188 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// campaignColumns lists campaign columns in the order expected by scanCampaign
+const campaignColumns = `c.id, c.code, c.title, c.multiplier, c.applies_to_tasks, c.applies_to_referrals,
+		c.budget, c.spent, c.starts_at, c.ends_at, c.is_active, c.created_at,
+		ARRAY(
+			SELECT tc.code FROM campaign_categories cc
+			JOIN task_categories tc ON tc.id = cc.category_id
+			WHERE cc.campaign_id = c.id
+			ORDER BY tc.code
+		)`
+
+// CampaignRepository handles campaign database operations
+type CampaignRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewCampaignRepository creates a new campaign repository
+func NewCampaignRepository(db *pgxpool.Pool) interfaces.CampaignRepository {
+	return &CampaignRepository{db: db}
+}
+
+// GetRunning retrieves active campaigns within their period that still have budget,
+// highest multiplier first
+func (r *CampaignRepository) GetRunning(ctx context.Context) ([]*entities.Campaign, error) {
+	query := `
+		SELECT ` + campaignColumns + `
+		FROM campaigns c
+		WHERE c.is_active = true
+		  AND c.starts_at <= now() AND c.ends_at > now()
+		  AND (c.budget IS NULL OR c.spent < c.budget)
+		ORDER BY c.multiplier DESC, c.id ASC`
+
+	return r.query(ctx, query)
+}
+
+// GetAll retrieves all campaigns, latest first
+func (r *CampaignRepository) GetAll(ctx context.Context) ([]*entities.Campaign, error) {
+	query := `
+		SELECT ` + campaignColumns + `
+		FROM campaigns c
+		ORDER BY c.starts_at DESC, c.id DESC`
+
+	return r.query(ctx, query)
+}
+
+// Create inserts a campaign limited to its categories, unknown category codes and
+// codes of existing campaigns are rejected
+func (r *CampaignRepository) Create(ctx context.Context, campaign *entities.Campaign) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx) // Rollback on error
+	}()
+
+	query := `
+		INSERT INTO campaigns (code, title, multiplier, applies_to_tasks, applies_to_referrals, budget, starts_at, ends_at, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, spent, created_at`
+
+	err = tx.QueryRow(
+		ctx,
+		query,
+		campaign.Code,
+		campaign.Title,
+		campaign.Multiplier,
+		campaign.AppliesToTasks,
+		campaign.AppliesToReferrals,
+		campaign.Budget,
+		campaign.StartsAt,
+		campaign.EndsAt,
+		campaign.IsActive,
+	).Scan(&campaign.ID, &campaign.Spent, &campaign.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return &entities.CampaignExistsError{Code: campaign.Code}
+		}
+		return err
+	}
+
+	if len(campaign.Categories) > 0 {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO campaign_categories (campaign_id, category_id)
+			SELECT $1, id FROM task_categories WHERE code = ANY($2)`, campaign.ID, campaign.Categories)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() != int64(len(campaign.Categories)) {
+			return &entities.InvalidCampaignError{Reason: "unknown category"}
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Spend charges up to amount points to the budget of a running campaign and
+// returns the charged points, which are less than amount once the budget runs out
+func (r *CampaignRepository) Spend(ctx context.Context, campaignID, amount int64) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx) // Rollback on error
+	}()
+
+	var budget *int64
+	var spent int64
+	err = tx.QueryRow(ctx, `
+		SELECT budget, spent FROM campaigns
+		WHERE id = $1 AND is_active = true AND starts_at <= now() AND ends_at > now()
+		FOR UPDATE`, campaignID).Scan(&budget, &spent)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, nil // Campaign is over
+		}
+		return 0, err
+	}
+
+	charged := amount
+	if budget != nil {
+		charged = min(amount, max(*budget-spent, 0))
+	}
+	if charged <= 0 {
+		return 0, nil
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE campaigns SET spent = spent + $2 WHERE id = $1`, campaignID, charged); err != nil {
+		return 0, err
+	}
+
+	return charged, tx.Commit(ctx)
+}
+
+// Refund returns points charged by Spend to the campaign budget
+func (r *CampaignRepository) Refund(ctx context.Context, campaignID, amount int64) error {
+	_, err := r.db.Exec(ctx, `UPDATE campaigns SET spent = GREATEST(spent - $2, 0) WHERE id = $1`, campaignID, amount)
+	return err
+}
+
+// query runs a query selecting campaignColumns
+func (r *CampaignRepository) query(ctx context.Context, query string, args ...any) ([]*entities.Campaign, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var campaigns []*entities.Campaign
+	for rows.Next() {
+		var campaign entities.Campaign
+		err := rows.Scan(
+			&campaign.ID,
+			&campaign.Code,
+			&campaign.Title,
+			&campaign.Multiplier,
+			&campaign.AppliesToTasks,
+			&campaign.AppliesToReferrals,
+			&campaign.Budget,
+			&campaign.Spent,
+			&campaign.StartsAt,
+			&campaign.EndsAt,
+			&campaign.IsActive,
+			&campaign.CreatedAt,
+			&campaign.Categories,
+		)
+		if err != nil {
+			return nil, err
+		}
+		campaigns = append(campaigns, &campaign)
+	}
+
+	return campaigns, rows.Err()
+}
@@ -0,0 +1,75 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CurrencyRepository handles currency-related database operations
+type CurrencyRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewCurrencyRepository creates a new currency repository
+func NewCurrencyRepository(db *pgxpool.Pool) interfaces.CurrencyRepository {
+	return &CurrencyRepository{db: db}
+}
+
+// GetByCode retrieves a currency by its code
+func (r *CurrencyRepository) GetByCode(ctx context.Context, code string) (*entities.Currency, error) {
+	query := `
+		SELECT code, title, is_spendable, created_at
+		FROM currencies
+		WHERE code = $1`
+
+	var currency entities.Currency
+	err := r.db.QueryRow(ctx, query, code).Scan(
+		&currency.Code,
+		&currency.Title,
+		&currency.IsSpendable,
+		&currency.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // Currency not found
+		}
+		return nil, err
+	}
+
+	return &currency, nil
+}
+
+// GetAll retrieves all currencies
+func (r *CurrencyRepository) GetAll(ctx context.Context) ([]*entities.Currency, error) {
+	query := `
+		SELECT code, title, is_spendable, created_at
+		FROM currencies
+		ORDER BY created_at ASC, code ASC`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var currencies []*entities.Currency
+	for rows.Next() {
+		var currency entities.Currency
+		err := rows.Scan(
+			&currency.Code,
+			&currency.Title,
+			&currency.IsSpendable,
+			&currency.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		currencies = append(currencies, &currency)
+	}
+
+	return currencies, rows.Err()
+}
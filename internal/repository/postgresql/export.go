@@ -0,0 +1,151 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// dataExportColumns lists data export columns, without content, in the order expected by scanDataExport
+const dataExportColumns = `id, user_id, format, status, size_bytes, error, created_at, completed_at, expires_at`
+
+// DataExportRepository handles personal data export database operations
+type DataExportRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewDataExportRepository creates a new data export repository
+func NewDataExportRepository(db *pgxpool.Pool) interfaces.DataExportRepository {
+	return &DataExportRepository{db: db}
+}
+
+// Create queues a data export
+func (r *DataExportRepository) Create(ctx context.Context, export *entities.DataExport) error {
+	query := `
+		INSERT INTO data_exports (user_id, format)
+		VALUES ($1, $2)
+		RETURNING id, status, created_at`
+
+	return r.db.QueryRow(ctx, query, export.UserID, export.Format).Scan(&export.ID, &export.Status, &export.CreatedAt)
+}
+
+// GetByID retrieves a data export, with the archive if withContent is set
+func (r *DataExportRepository) GetByID(ctx context.Context, id int64, withContent bool) (*entities.DataExport, error) {
+	content := "NULL::BYTEA"
+	if withContent {
+		content = "content"
+	}
+
+	row := r.db.QueryRow(ctx, `SELECT `+dataExportColumns+`, `+content+` FROM data_exports WHERE id = $1`, id)
+
+	var export entities.DataExport
+	if err := scanDataExport(row, &export, &export.Content); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // Export not found
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+// GetByUserID retrieves data exports of a user without archives, latest first
+func (r *DataExportRepository) GetByUserID(ctx context.Context, userID int64) ([]*entities.DataExport, error) {
+	query := `
+		SELECT ` + dataExportColumns + `
+		FROM data_exports
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exports []*entities.DataExport
+	for rows.Next() {
+		var export entities.DataExport
+		if err := scanDataExport(rows, &export); err != nil {
+			return nil, err
+		}
+		exports = append(exports, &export)
+	}
+
+	return exports, rows.Err()
+}
+
+// ClaimNext marks the oldest pending export as processing and returns it. Exports
+// processing for longer than staleAfter are claimed again, their worker is gone
+func (r *DataExportRepository) ClaimNext(ctx context.Context, staleAfter time.Duration) (*entities.DataExport, error) {
+	query := `
+		UPDATE data_exports
+		SET status = 'processing', started_at = now()
+		WHERE id = (
+			SELECT id FROM data_exports
+			WHERE status = 'pending'
+			   OR (status = 'processing' AND started_at < now() - make_interval(secs => $1))
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + dataExportColumns
+
+	var export entities.DataExport
+	if err := scanDataExport(r.db.QueryRow(ctx, query, staleAfter.Seconds()), &export); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // Queue is empty
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+// Complete stores the archive of an export, downloadable until expiresAt
+func (r *DataExportRepository) Complete(ctx context.Context, id int64, content []byte, expiresAt time.Time) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE data_exports
+		SET status = 'ready', content = $2, size_bytes = $3, completed_at = now(), expires_at = $4
+		WHERE id = $1`, id, content, len(content), expiresAt)
+	return err
+}
+
+// Fail marks an export as failed with the reason
+func (r *DataExportRepository) Fail(ctx context.Context, id int64, reason string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE data_exports
+		SET status = 'failed', error = $2, completed_at = now()
+		WHERE id = $1`, id, reason)
+	return err
+}
+
+// ExpireBefore drops archives of exports that expired before now and returns how many expired
+func (r *DataExportRepository) ExpireBefore(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE data_exports
+		SET status = 'expired', content = NULL
+		WHERE status = 'ready' AND expires_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// scanDataExport scans a row of dataExportColumns followed by extra destinations
+func scanDataExport(row pgx.Row, export *entities.DataExport, extra ...any) error {
+	dest := []any{
+		&export.ID,
+		&export.UserID,
+		&export.Format,
+		&export.Status,
+		&export.SizeBytes,
+		&export.Error,
+		&export.CreatedAt,
+		&export.CompletedAt,
+		&export.ExpiresAt,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
@@ -0,0 +1,71 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LevelRepository handles level-up event database operations
+type LevelRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewLevelRepository creates a new level repository
+func NewLevelRepository(db *pgxpool.Pool) interfaces.LevelRepository {
+	return &LevelRepository{db: db}
+}
+
+// CreateLevelUp records a level-up event
+func (r *LevelRepository) CreateLevelUp(ctx context.Context, levelUp *entities.LevelUp) error {
+	query := `
+		INSERT INTO level_ups (user_id, level, name, lifetime_points, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`
+
+	return r.db.QueryRow(
+		ctx,
+		query,
+		levelUp.UserID,
+		levelUp.Level,
+		levelUp.Name,
+		levelUp.LifetimePoints,
+		levelUp.CreatedAt,
+	).Scan(&levelUp.ID)
+}
+
+// GetLevelUpsByUserID retrieves level-up history for a user, newest first
+func (r *LevelRepository) GetLevelUpsByUserID(ctx context.Context, userID int64) ([]*entities.LevelUp, error) {
+	query := `
+		SELECT id, user_id, level, name, lifetime_points, created_at
+		FROM level_ups
+		WHERE user_id = $1
+		ORDER BY created_at DESC`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var levelUps []*entities.LevelUp
+	for rows.Next() {
+		var levelUp entities.LevelUp
+		err := rows.Scan(
+			&levelUp.ID,
+			&levelUp.UserID,
+			&levelUp.Level,
+			&levelUp.Name,
+			&levelUp.LifetimePoints,
+			&levelUp.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		levelUps = append(levelUps, &levelUp)
+	}
+
+	return levelUps, rows.Err()
+}
@@ -0,0 +1,72 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PartnerRepository handles partner-related database operations
+type PartnerRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPartnerRepository creates a new partner repository
+func NewPartnerRepository(db *pgxpool.Pool) interfaces.PartnerRepository {
+	return &PartnerRepository{db: db}
+}
+
+// GetByCode retrieves a partner with its secret by code
+func (r *PartnerRepository) GetByCode(ctx context.Context, code string) (*entities.Partner, error) {
+	query := `
+		SELECT id, code, title, secret, is_active, created_at
+		FROM partners
+		WHERE code = $1`
+
+	var partner entities.Partner
+	err := r.db.QueryRow(ctx, query, code).Scan(
+		&partner.ID,
+		&partner.Code,
+		&partner.Title,
+		&partner.Secret,
+		&partner.IsActive,
+		&partner.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // Partner not found
+		}
+		return nil, err
+	}
+
+	return &partner, nil
+}
+
+// UseNonce records a callback nonce and reports whether it was not used before
+func (r *PartnerRepository) UseNonce(ctx context.Context, partnerID int64, nonce string) (bool, error) {
+	query := `
+		INSERT INTO partner_nonces (partner_id, nonce)
+		VALUES ($1, $2)
+		ON CONFLICT (partner_id, nonce) DO NOTHING`
+
+	result, err := r.db.Exec(ctx, query, partnerID, nonce)
+	if err != nil {
+		return false, err
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// PurgeNonces deletes nonces recorded before the given time and returns how many were deleted
+func (r *PartnerRepository) PurgeNonces(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.Exec(ctx, `DELETE FROM partner_nonces WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,123 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// QuestChainRepository handles quest chain database operations
+type QuestChainRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewQuestChainRepository creates a new quest chain repository
+func NewQuestChainRepository(db *pgxpool.Pool) interfaces.QuestChainRepository {
+	return &QuestChainRepository{db: db}
+}
+
+// GetByID retrieves a quest chain with IDs of its tasks
+func (r *QuestChainRepository) GetByID(ctx context.Context, id int64) (*entities.QuestChain, error) {
+	query := `
+		SELECT c.id, c.code, c.title, c.bonus_points, c.is_active, c.created_at,
+		       ARRAY(SELECT t.id FROM tasks t WHERE t.chain_id = c.id ORDER BY t.id)
+		FROM quest_chains c
+		WHERE c.id = $1`
+
+	var chain entities.QuestChain
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&chain.ID,
+		&chain.Code,
+		&chain.Title,
+		&chain.BonusPoints,
+		&chain.IsActive,
+		&chain.CreatedAt,
+		&chain.TaskIDs,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // Chain not found
+		}
+		return nil, err
+	}
+
+	return &chain, nil
+}
+
+// GetUserChains retrieves all active quest chains with the user's progress
+func (r *QuestChainRepository) GetUserChains(ctx context.Context, userID int64) ([]*entities.UserQuestChain, error) {
+	query := `
+		SELECT c.id, c.code, c.title, c.bonus_points, c.is_active, c.created_at,
+		       ARRAY(SELECT t.id FROM tasks t WHERE t.chain_id = c.id ORDER BY t.id),
+		       ARRAY(
+				SELECT t.id FROM tasks t
+				WHERE t.chain_id = c.id
+				  AND EXISTS (
+					SELECT 1 FROM user_tasks ut
+					WHERE ut.task_id = t.id AND ut.user_id = $1 AND ut.status = 'completed'
+				  )
+				ORDER BY t.id
+		       ),
+		       uqc.completed_at
+		FROM quest_chains c
+		LEFT JOIN user_quest_chains uqc ON uqc.chain_id = c.id AND uqc.user_id = $1
+		WHERE c.is_active = true
+		ORDER BY c.id ASC`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chains []*entities.UserQuestChain
+	for rows.Next() {
+		var chain entities.UserQuestChain
+		err := rows.Scan(
+			&chain.ID,
+			&chain.Code,
+			&chain.Title,
+			&chain.BonusPoints,
+			&chain.IsActive,
+			&chain.CreatedAt,
+			&chain.TaskIDs,
+			&chain.CompletedTaskIDs,
+			&chain.CompletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		chains = append(chains, &chain)
+	}
+
+	return chains, rows.Err()
+}
+
+// Complete records the chain as completed if the user has completed all of its active
+// tasks, and reports whether it was newly completed
+func (r *QuestChainRepository) Complete(ctx context.Context, userID, chainID int64) (bool, error) {
+	query := `
+		INSERT INTO user_quest_chains (user_id, chain_id, completed_at)
+		SELECT $1, c.id, CURRENT_TIMESTAMP
+		FROM quest_chains c
+		WHERE c.id = $2 AND c.is_active = true
+		  AND NOT EXISTS (
+			SELECT 1 FROM tasks t
+			WHERE t.chain_id = c.id AND t.is_active = true
+			  AND NOT EXISTS (
+				SELECT 1 FROM user_tasks ut
+				WHERE ut.task_id = t.id AND ut.user_id = $1 AND ut.status = 'completed'
+			  )
+		  )
+		ON CONFLICT (user_id, chain_id) DO NOTHING`
+
+	result, err := r.db.Exec(ctx, query, userID, chainID)
+	if err != nil {
+		return false, err
+	}
+
+	return result.RowsAffected() > 0, nil
+}
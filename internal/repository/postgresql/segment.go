@@ -0,0 +1,138 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SegmentRepository handles user segment and task audience database operations
+type SegmentRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewSegmentRepository creates a new segment repository
+func NewSegmentRepository(db *pgxpool.Pool) interfaces.SegmentRepository {
+	return &SegmentRepository{db: db}
+}
+
+// GetAudience retrieves the user attributes checked by task targeting rules and the
+// account status. Level is not stored and left for the caller to derive from lifetime points
+func (r *SegmentRepository) GetAudience(ctx context.Context, userID int64) (*entities.Audience, error) {
+	query := `
+		SELECT u.id, u.created_at, u.referrer_id IS NOT NULL,
+		       u.status, u.status_reason, u.status_until, u.deleted_at,
+		       COALESCE(b.lifetime_points, 0),
+		       ARRAY(SELECT m.segment_id FROM user_segment_members m WHERE m.user_id = u.id ORDER BY m.segment_id)
+		FROM users u
+		LEFT JOIN balances b ON b.user_id = u.id AND b.currency = $2
+		WHERE u.id = $1`
+
+	var audience entities.Audience
+	err := r.db.QueryRow(ctx, query, userID, entities.DefaultCurrency).Scan(
+		&audience.UserID,
+		&audience.CreatedAt,
+		&audience.HasReferrer,
+		&audience.AccountStatus.Status,
+		&audience.AccountStatus.StatusReason,
+		&audience.AccountStatus.StatusUntil,
+		&audience.AccountStatus.DeletedAt,
+		&audience.LifetimePoints,
+		&audience.SegmentIDs,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // User not found
+		}
+		return nil, err
+	}
+
+	return &audience, nil
+}
+
+// GetAll retrieves all segments with their member counts
+func (r *SegmentRepository) GetAll(ctx context.Context) ([]*entities.Segment, error) {
+	query := `
+		SELECT s.id, s.code, s.title, s.created_at,
+		       (SELECT COUNT(*) FROM user_segment_members m WHERE m.segment_id = s.id)
+		FROM user_segments s
+		ORDER BY s.code`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var segments []*entities.Segment
+	for rows.Next() {
+		var segment entities.Segment
+		if err := rows.Scan(&segment.ID, &segment.Code, &segment.Title, &segment.CreatedAt, &segment.MemberCount); err != nil {
+			return nil, err
+		}
+		segments = append(segments, &segment)
+	}
+
+	return segments, rows.Err()
+}
+
+// GetByCode retrieves a segment by its code
+func (r *SegmentRepository) GetByCode(ctx context.Context, code string) (*entities.Segment, error) {
+	query := `
+		SELECT s.id, s.code, s.title, s.created_at,
+		       (SELECT COUNT(*) FROM user_segment_members m WHERE m.segment_id = s.id)
+		FROM user_segments s
+		WHERE s.code = $1`
+
+	var segment entities.Segment
+	err := r.db.QueryRow(ctx, query, code).Scan(&segment.ID, &segment.Code, &segment.Title, &segment.CreatedAt, &segment.MemberCount)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // Segment not found
+		}
+		return nil, err
+	}
+
+	return &segment, nil
+}
+
+// Save creates a segment or updates the title of an existing one with the same code
+func (r *SegmentRepository) Save(ctx context.Context, segment *entities.Segment) error {
+	query := `
+		INSERT INTO user_segments (code, title)
+		VALUES ($1, $2)
+		ON CONFLICT (code) DO UPDATE SET title = EXCLUDED.title
+		RETURNING id, created_at,
+		          (SELECT COUNT(*) FROM user_segment_members m WHERE m.segment_id = user_segments.id)`
+
+	return r.db.QueryRow(ctx, query, segment.Code, segment.Title).Scan(&segment.ID, &segment.CreatedAt, &segment.MemberCount)
+}
+
+// AddMembers adds existing users to a segment, skipping current members,
+// and returns the number of added users
+func (r *SegmentRepository) AddMembers(ctx context.Context, segmentID int64, userIDs []int64) (int64, error) {
+	query := `
+		INSERT INTO user_segment_members (segment_id, user_id)
+		SELECT $1, u.id
+		FROM users u
+		WHERE u.id = ANY($2)
+		ON CONFLICT DO NOTHING`
+
+	tag, err := r.db.Exec(ctx, query, segmentID, userIDs)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// RemoveMember removes a user from a segment and reports whether the user was a member
+func (r *SegmentRepository) RemoveMember(ctx context.Context, segmentID, userID int64) (bool, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM user_segment_members WHERE segment_id = $1 AND user_id = $2`, segmentID, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
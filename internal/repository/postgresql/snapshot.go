@@ -0,0 +1,53 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// snapshotKey keys the snapshot transaction in a context
+type snapshotKey struct{}
+
+// querier runs read queries on the pool or on a transaction
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// SnapshotReader runs reads in one repeatable read transaction
+type SnapshotReader struct {
+	db *pgxpool.Pool
+}
+
+// NewSnapshotReader creates a new snapshot reader
+func NewSnapshotReader(db *pgxpool.Pool) interfaces.SnapshotReader {
+	return &SnapshotReader{db: db}
+}
+
+// ReadSnapshot runs read in a read-only repeatable read transaction. Repository reads
+// given the context passed to read see the same snapshot, writes are rejected
+func (s *SnapshotReader) ReadSnapshot(ctx context.Context, read func(ctx context.Context) error) error {
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx) // Rollback on error
+	}()
+
+	if err := read(context.WithValue(ctx, snapshotKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// reader returns the snapshot transaction of ctx, or db outside a snapshot
+func reader(ctx context.Context, db *pgxpool.Pool) querier {
+	if tx, ok := ctx.Value(snapshotKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return db
+}
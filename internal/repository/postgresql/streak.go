@@ -0,0 +1,208 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// StreakRepository handles streak-related database operations
+type StreakRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewStreakRepository creates a new streak repository
+func NewStreakRepository(db *pgxpool.Pool) interfaces.StreakRepository {
+	return &StreakRepository{db: db}
+}
+
+// Get retrieves a user's streak for a task
+func (r *StreakRepository) Get(ctx context.Context, userID, taskID int64) (*entities.Streak, error) {
+	query := `
+		SELECT user_id, task_id, current_streak, longest_streak, last_period_start, updated_at
+		FROM user_streaks
+		WHERE user_id = $1 AND task_id = $2`
+
+	var streak entities.Streak
+	err := r.db.QueryRow(ctx, query, userID, taskID).Scan(
+		&streak.UserID,
+		&streak.TaskID,
+		&streak.Current,
+		&streak.Longest,
+		&streak.LastPeriodStart,
+		&streak.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // Streak not started yet
+		}
+		return nil, err
+	}
+
+	return &streak, nil
+}
+
+// Save creates or updates a user's streak for a task
+func (r *StreakRepository) Save(ctx context.Context, streak *entities.Streak) error {
+	query := `
+		INSERT INTO user_streaks (user_id, task_id, current_streak, longest_streak, last_period_start, updated_at)
+		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)
+		ON CONFLICT (user_id, task_id) DO UPDATE
+		SET current_streak = EXCLUDED.current_streak,
+		    longest_streak = EXCLUDED.longest_streak,
+		    last_period_start = EXCLUDED.last_period_start,
+		    updated_at = EXCLUDED.updated_at
+		RETURNING updated_at`
+
+	return r.db.QueryRow(
+		ctx,
+		query,
+		streak.UserID,
+		streak.TaskID,
+		streak.Current,
+		streak.Longest,
+		streak.LastPeriodStart,
+	).Scan(&streak.UpdatedAt)
+}
+
+// GetByUserID retrieves all streaks of a user with task details
+func (r *StreakRepository) GetByUserID(ctx context.Context, userID int64) ([]*entities.Streak, error) {
+	query := `
+		SELECT s.user_id, s.task_id, t.code, t.title,
+		       s.current_streak, s.longest_streak, s.last_period_start, s.updated_at
+		FROM user_streaks s
+		JOIN tasks t ON s.task_id = t.id
+		WHERE s.user_id = $1
+		ORDER BY s.current_streak DESC, s.task_id ASC`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var streaks []*entities.Streak
+	for rows.Next() {
+		var streak entities.Streak
+		err := rows.Scan(
+			&streak.UserID,
+			&streak.TaskID,
+			&streak.TaskCode,
+			&streak.TaskTitle,
+			&streak.Current,
+			&streak.Longest,
+			&streak.LastPeriodStart,
+			&streak.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		streaks = append(streaks, &streak)
+	}
+
+	return streaks, rows.Err()
+}
+
+// GetFreezes retrieves the number of streak freezes a user owns
+func (r *StreakRepository) GetFreezes(ctx context.Context, userID int64) (int, error) {
+	query := `
+		SELECT available
+		FROM streak_freezes
+		WHERE user_id = $1`
+
+	var available int
+	err := r.db.QueryRow(ctx, query, userID).Scan(&available)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	return available, nil
+}
+
+// AddFreezes changes the number of streak freezes a user owns by delta,
+// ensuring it doesn't go negative
+func (r *StreakRepository) AddFreezes(ctx context.Context, userID int64, delta int) error {
+	query := `
+		INSERT INTO streak_freezes (user_id, available, updated_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP)
+		ON CONFLICT (user_id) DO UPDATE
+		SET available = streak_freezes.available + EXCLUDED.available,
+		    updated_at = EXCLUDED.updated_at
+		WHERE streak_freezes.available + EXCLUDED.available >= 0`
+
+	if delta < 0 {
+		// Spending freezes requires an existing row with enough items
+		query = `
+		UPDATE streak_freezes
+		SET available = available + $2, updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND available + $2 >= 0`
+	}
+
+	result, err := r.db.Exec(ctx, query, userID, delta)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return &entities.InsufficientFreezesError{UserID: userID}
+	}
+
+	return nil
+}
+
+// PurchaseFreeze adds a streak freeze paid by payment, a negative transaction of the
+// user, in one transaction: the balance is charged, the payment recorded and the freeze
+// added together or not at all. A nil payment adds a free freeze. Returns the number
+// of available freezes
+func (r *StreakRepository) PurchaseFreeze(ctx context.Context, userID int64, payment *entities.Transaction) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx) // Rollback on error
+	}()
+
+	if payment != nil {
+		result, err := tx.Exec(ctx, `
+			UPDATE balances
+			SET points = points + $3, updated_at = CURRENT_TIMESTAMP
+			WHERE user_id = $1 AND currency = $2 AND (points + $3) >= 0`, userID, payment.Currency, payment.Delta)
+		if err != nil {
+			return 0, err
+		}
+		if result.RowsAffected() == 0 {
+			return 0, &entities.InsufficientBalanceError{UserID: userID}
+		}
+
+		err = tx.QueryRow(ctx, `
+			INSERT INTO transactions (user_id, currency, delta, reason, reference_type, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id`,
+			userID, payment.Currency, payment.Delta, payment.Reason, payment.ReferenceType, payment.CreatedAt,
+		).Scan(&payment.ID)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	var available int
+	err = tx.QueryRow(ctx, `
+		INSERT INTO streak_freezes (user_id, available, updated_at)
+		VALUES ($1, 1, CURRENT_TIMESTAMP)
+		ON CONFLICT (user_id) DO UPDATE
+		SET available = streak_freezes.available + 1,
+		    updated_at = EXCLUDED.updated_at
+		RETURNING available`, userID).Scan(&available)
+	if err != nil {
+		return 0, err
+	}
+
+	return available, tx.Commit(ctx)
+}
@@ -9,6 +9,13 @@ import (
 	"github.com/abdullinmm/user-management-api/internal/domain/entities"
 )
 
+// taskColumns lists task columns in the order expected by scanTask
+const taskColumns = `t.id, t.code, t.title, t.reward_points, t.is_active, t.created_at,
+		t.recurrence, t.recurrence_hours, t.starts_at, t.ends_at,
+		t.max_completions, t.max_per_user, t.completions, t.chain_id, t.verification,
+		t.partner_id, t.category_id, t.description, t.icon_url, t.sort_order,
+		t.min_level, t.min_points, t.min_account_age_days, t.has_referrer, t.version`
+
 // TaskRepository represents a repository for tasks
 type TaskRepository struct {
 	db *pgxpool.Pool
@@ -22,19 +29,11 @@ func NewTaskRepository(db *pgxpool.Pool) *TaskRepository {
 // GetByID retrieves a task by its ID
 func (r *TaskRepository) GetByID(ctx context.Context, id int64) (*entities.Task, error) {
 	query := `
-		SELECT id, code, title, reward_points, is_active, created_at
-		FROM tasks
-		WHERE id = $1`
+		SELECT ` + taskColumns + `
+		FROM tasks t
+		WHERE t.id = $1`
 
-	var task entities.Task
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&task.ID,
-		&task.Code,
-		&task.Title,
-		&task.RewardPoints,
-		&task.IsActive,
-		&task.CreatedAt,
-	)
+	task, err := scanTask(r.db.QueryRow(ctx, query, id))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil // Task not found
@@ -42,45 +41,153 @@ func (r *TaskRepository) GetByID(ctx context.Context, id int64) (*entities.Task,
 		return nil, err
 	}
 
-	return &task, nil
+	if err := loadTaskDetails(ctx, r.db, task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
 }
 
 // GetByCode retrieves a task by its code
 func (r *TaskRepository) GetByCode(ctx context.Context, code string) (*entities.Task, error) {
 	query := `
-	SELECT id, code,title, reward_points, is_active, created_at
-	FROM tasks
-	WHERE code = $1`
+		SELECT ` + taskColumns + `
+		FROM tasks t
+		WHERE t.code = $1`
+
+	task, err := scanTask(r.db.QueryRow(ctx, query, code))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // Task not found
+		}
+		return nil, err
+	}
 
+	if err := loadTaskDetails(ctx, r.db, task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// GetActive retrieves all active tasks that haven't expired ordered by category and sort order
+func (r *TaskRepository) GetActive(ctx context.Context) ([]*entities.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks t
+		LEFT JOIN task_categories c ON c.id = t.category_id
+		WHERE t.is_active = true
+		  AND (t.ends_at IS NULL OR t.ends_at > now())
+		ORDER BY c.sort_order NULLS LAST, t.sort_order, t.created_at ASC`
+
+	return queryTasks(ctx, r.db, query)
+}
+
+// GetAll retrieves all tasks (both active and inactive)
+func (r *TaskRepository) GetAll(ctx context.Context) ([]*entities.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks t
+		ORDER BY t.created_at DESC`
+
+	return queryTasks(ctx, r.db, query)
+}
+
+// GetTranslations retrieves translations of the given tasks into the given locales
+func (r *TaskRepository) GetTranslations(ctx context.Context, taskIDs []int64, locales []string) ([]*entities.TaskTranslation, error) {
+	if len(taskIDs) == 0 || len(locales) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT task_id, locale, title, description
+		FROM task_translations
+		WHERE task_id = ANY($1) AND locale = ANY($2)`
+
+	rows, err := r.db.Query(ctx, query, taskIDs, locales)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var translations []*entities.TaskTranslation
+	for rows.Next() {
+		var tr entities.TaskTranslation
+		if err := rows.Scan(&tr.TaskID, &tr.Locale, &tr.Title, &tr.Description); err != nil {
+			return nil, err
+		}
+		translations = append(translations, &tr)
+	}
+
+	return translations, rows.Err()
+}
+
+// GetVersions retrieves the title and reward history of a task, latest version first
+func (r *TaskRepository) GetVersions(ctx context.Context, taskID int64) ([]*entities.TaskVersion, error) {
+	query := `
+		SELECT task_id, version, title, reward_points, created_at
+		FROM task_versions
+		WHERE task_id = $1
+		ORDER BY version DESC`
+
+	rows, err := r.db.Query(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []*entities.TaskVersion
+	for rows.Next() {
+		var version entities.TaskVersion
+		if err := rows.Scan(&version.TaskID, &version.Version, &version.Title, &version.RewardPoints, &version.CreatedAt); err != nil {
+			return nil, err
+		}
+		versions = append(versions, &version)
+	}
+
+	return versions, rows.Err()
+}
+
+// scanTask scans a row of taskColumns into a task
+func scanTask(row pgx.Row) (*entities.Task, error) {
 	var task entities.Task
-	err := r.db.QueryRow(ctx, query, code).Scan(
+	err := row.Scan(
 		&task.ID,
 		&task.Code,
 		&task.Title,
 		&task.RewardPoints,
 		&task.IsActive,
 		&task.CreatedAt,
+		&task.Recurrence,
+		&task.RecurrenceHours,
+		&task.StartsAt,
+		&task.EndsAt,
+		&task.MaxCompletions,
+		&task.MaxPerUser,
+		&task.Completions,
+		&task.ChainID,
+		&task.Verification,
+		&task.PartnerID,
+		&task.CategoryID,
+		&task.Description,
+		&task.IconURL,
+		&task.SortOrder,
+		&task.Targeting.MinLevel,
+		&task.Targeting.MinPoints,
+		&task.Targeting.MinAccountAgeDays,
+		&task.Targeting.HasReferrer,
+		&task.Version,
 	)
-
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, nil // ← Добавьте правильный return
-		}
 		return nil, err
 	}
-
+	task.RemainingSlots = task.Remaining()
 	return &task, nil
 }
 
-// GetActive retrieves all active tasks
-func (r *TaskRepository) GetActive(ctx context.Context) ([]*entities.Task, error) {
-	query := `
-		SELECT id, code, title, reward_points, is_active, created_at
-		FROM tasks
-		WHERE is_active = true
-		ORDER BY created_at ASC`
-
-	rows, err := r.db.Query(ctx, query)
+// queryTasks runs a query selecting taskColumns and loads details of the returned tasks
+func queryTasks(ctx context.Context, db *pgxpool.Pool, query string, args ...any) ([]*entities.Task, error) {
+	rows, err := db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -88,49 +195,230 @@ func (r *TaskRepository) GetActive(ctx context.Context) ([]*entities.Task, error
 
 	var tasks []*entities.Task
 	for rows.Next() {
-		var task entities.Task
-		err := rows.Scan(
-			&task.ID, &task.Code, &task.Title,
-			&task.RewardPoints, &task.IsActive, &task.CreatedAt,
-		)
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, err
 		}
-		tasks = append(tasks, &task)
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := loadTaskDetails(ctx, db, tasks...); err != nil {
+		return nil, err
 	}
 
-	return tasks, rows.Err()
+	return tasks, nil
 }
 
-// GetAll retrieves all tasks (both active and inactive)
-func (r *TaskRepository) GetAll(ctx context.Context) ([]*entities.Task, error) {
+// loadTaskDetails fills rewards, prerequisites, categories, tags and target segments of the given tasks
+func loadTaskDetails(ctx context.Context, db *pgxpool.Pool, tasks ...*entities.Task) error {
+	if err := loadExtraRewards(ctx, db, tasks...); err != nil {
+		return err
+	}
+	if err := loadPrerequisites(ctx, db, tasks...); err != nil {
+		return err
+	}
+	if err := loadCategories(ctx, db, tasks...); err != nil {
+		return err
+	}
+	if err := loadTags(ctx, db, tasks...); err != nil {
+		return err
+	}
+	return loadTargetSegments(ctx, db, tasks...)
+}
+
+// loadExtraRewards fills rewards in non-default currencies for the given tasks
+func loadExtraRewards(ctx context.Context, db *pgxpool.Pool, tasks ...*entities.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(tasks))
+	byID := make(map[int64]*entities.Task, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+		byID[task.ID] = task
+	}
+
 	query := `
-		SELECT id, code, title, reward_points, is_active, created_at
-		FROM tasks
-		ORDER BY created_at DESC`
+		SELECT task_id, currency, amount
+		FROM task_rewards
+		WHERE task_id = ANY($1)
+		ORDER BY task_id, currency`
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := db.Query(ctx, query, ids)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	var tasks []*entities.Task
 	for rows.Next() {
-		var task entities.Task
-		err := rows.Scan(
-			&task.ID,
-			&task.Code,
-			&task.Title,
-			&task.RewardPoints,
-			&task.IsActive,
-			&task.CreatedAt,
-		)
-		if err != nil {
-			return nil, err
+		var reward entities.TaskReward
+		if err := rows.Scan(&reward.TaskID, &reward.Currency, &reward.Amount); err != nil {
+			return err
+		}
+		task := byID[reward.TaskID]
+		task.ExtraRewards = append(task.ExtraRewards, reward)
+	}
+
+	return rows.Err()
+}
+
+// loadPrerequisites fills IDs of tasks that must be completed before the given tasks
+func loadPrerequisites(ctx context.Context, db *pgxpool.Pool, tasks ...*entities.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(tasks))
+	byID := make(map[int64]*entities.Task, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+		byID[task.ID] = task
+	}
+
+	query := `
+		SELECT task_id, prerequisite_id
+		FROM task_prerequisites
+		WHERE task_id = ANY($1)
+		ORDER BY task_id, prerequisite_id`
+
+	rows, err := db.Query(ctx, query, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID, prerequisiteID int64
+		if err := rows.Scan(&taskID, &prerequisiteID); err != nil {
+			return err
+		}
+		task := byID[taskID]
+		task.Prerequisites = append(task.Prerequisites, prerequisiteID)
+	}
+
+	return rows.Err()
+}
+
+// loadCategories fills categories of the given tasks
+func loadCategories(ctx context.Context, db *pgxpool.Pool, tasks ...*entities.Task) error {
+	var ids []int64
+	for _, task := range tasks {
+		if task.CategoryID != nil {
+			ids = append(ids, *task.CategoryID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := `
+		SELECT id, code, title, sort_order
+		FROM task_categories
+		WHERE id = ANY($1)`
+
+	rows, err := db.Query(ctx, query, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	byID := make(map[int64]*entities.TaskCategory)
+	for rows.Next() {
+		var category entities.TaskCategory
+		if err := rows.Scan(&category.ID, &category.Code, &category.Title, &category.SortOrder); err != nil {
+			return err
+		}
+		byID[category.ID] = &category
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		if task.CategoryID != nil {
+			task.Category = byID[*task.CategoryID]
+		}
+	}
+
+	return nil
+}
+
+// loadTags fills tags of the given tasks
+func loadTags(ctx context.Context, db *pgxpool.Pool, tasks ...*entities.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(tasks))
+	byID := make(map[int64]*entities.Task, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+		byID[task.ID] = task
+		task.Tags = []string{}
+	}
+
+	query := `
+		SELECT task_id, tag
+		FROM task_tags
+		WHERE task_id = ANY($1)
+		ORDER BY task_id, tag`
+
+	rows, err := db.Query(ctx, query, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID int64
+		var tag string
+		if err := rows.Scan(&taskID, &tag); err != nil {
+			return err
+		}
+		task := byID[taskID]
+		task.Tags = append(task.Tags, tag)
+	}
+
+	return rows.Err()
+}
+
+// loadTargetSegments fills IDs of user segments the given tasks are targeted at
+func loadTargetSegments(ctx context.Context, db *pgxpool.Pool, tasks ...*entities.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(tasks))
+	byID := make(map[int64]*entities.Task, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+		byID[task.ID] = task
+	}
+
+	query := `
+		SELECT task_id, segment_id
+		FROM task_segments
+		WHERE task_id = ANY($1)
+		ORDER BY task_id, segment_id`
+
+	rows, err := db.Query(ctx, query, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID, segmentID int64
+		if err := rows.Scan(&taskID, &segmentID); err != nil {
+			return err
 		}
-		tasks = append(tasks, &task)
+		task := byID[taskID]
+		task.Targeting.SegmentIDs = append(task.Targeting.SegmentIDs, segmentID)
 	}
 
-	return tasks, rows.Err()
+	return rows.Err()
 }
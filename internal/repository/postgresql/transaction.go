@@ -2,10 +2,12 @@ package postgresql
 
 import (
 	"context"
+	"time"
 
 	"github.com/abdullinmm/user-management-api/internal/domain/entities"
 	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -21,19 +23,26 @@ func NewTransactionRepository(db *pgxpool.Pool) interfaces.TransactionRepository
 
 // Create creates a new transaction record
 func (r *TransactionRepository) Create(ctx context.Context, transaction *entities.Transaction) error {
+	if transaction.Currency == "" {
+		transaction.Currency = entities.DefaultCurrency
+	}
+
 	query := `
-		INSERT INTO transactions (user_id, delta, reason, reference_type, reference_id, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO transactions (user_id, currency, delta, reason, reference_type, reference_id, user_task_id, campaign_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id`
 
 	err := r.db.QueryRow(
 		ctx,
 		query,
 		transaction.UserID,
+		transaction.Currency,
 		transaction.Delta,
 		transaction.Reason,
 		transaction.ReferenceType,
 		transaction.ReferenceID,
+		transaction.UserTaskID,
+		transaction.CampaignID,
 		transaction.CreatedAt,
 	).Scan(&transaction.ID)
 
@@ -43,7 +52,7 @@ func (r *TransactionRepository) Create(ctx context.Context, transaction *entitie
 // GetByUserID retrieves all transactions for a specific user with pagination
 func (r *TransactionRepository) GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]*entities.Transaction, error) {
 	query := `
-		SELECT id, user_id, delta, reason, reference_type, reference_id, created_at
+		SELECT id, user_id, currency, delta, reason, reference_type, reference_id, user_task_id, campaign_id, created_at
 		FROM transactions
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -55,16 +64,43 @@ func (r *TransactionRepository) GetByUserID(ctx context.Context, userID int64, l
 	}
 	defer rows.Close()
 
+	return scanTransactions(rows)
+}
+
+// GetByUserIDAfter retrieves up to limit transactions of a user with IDs above afterID,
+// oldest first. Transactions created meanwhile can't shift pages read by ID
+func (r *TransactionRepository) GetByUserIDAfter(ctx context.Context, userID, afterID int64, limit int) ([]*entities.Transaction, error) {
+	query := `
+		SELECT id, user_id, currency, delta, reason, reference_type, reference_id, user_task_id, campaign_id, created_at
+		FROM transactions
+		WHERE user_id = $1 AND id > $2
+		ORDER BY id ASC
+		LIMIT $3`
+
+	rows, err := reader(ctx, r.db).Query(ctx, query, userID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTransactions(rows)
+}
+
+// scanTransactions scans rows of transaction columns
+func scanTransactions(rows pgx.Rows) ([]*entities.Transaction, error) {
 	var transactions []*entities.Transaction
 	for rows.Next() {
 		var tx entities.Transaction
 		err := rows.Scan(
 			&tx.ID,
 			&tx.UserID,
+			&tx.Currency,
 			&tx.Delta,
 			&tx.Reason,
 			&tx.ReferenceType,
 			&tx.ReferenceID,
+			&tx.UserTaskID,
+			&tx.CampaignID,
 			&tx.CreatedAt,
 		)
 		if err != nil {
@@ -75,3 +111,34 @@ func (r *TransactionRepository) GetByUserID(ctx context.Context, userID int64, l
 
 	return transactions, rows.Err()
 }
+
+// GetEarnedLeaderboard retrieves top users by points earned since the given time
+// (or over all time when since is nil) with ranks and pagination
+func (r *TransactionRepository) GetEarnedLeaderboard(ctx context.Context, currency string, since *time.Time, mode entities.RankingMode, limit, offset int) ([]*entities.LeaderboardEntry, error) {
+	query := `
+		WITH earned AS (
+			SELECT t.user_id, t.currency, SUM(t.delta) AS points, MAX(t.created_at) AS updated_at
+			FROM transactions t
+			WHERE t.currency = $1
+			  AND t.delta > 0
+			  -- Opening balances of imported users were not earned here
+			  AND t.reference_type IS DISTINCT FROM '` + entities.ImportReferenceType + `'
+			  AND ($2::timestamp IS NULL OR t.created_at >= $2)
+			GROUP BY t.user_id, t.currency
+		)
+		SELECT e.user_id, u.username, e.currency, e.points, e.updated_at,
+		       ` + rankFunction(mode) + ` OVER (ORDER BY e.points DESC) AS rank
+		FROM earned e
+		JOIN users u ON e.user_id = u.id
+		WHERE ` + activeUserCondition + `
+		ORDER BY e.points DESC, e.user_id ASC
+		LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.Query(ctx, query, currency, since, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanLeaderboard(rows)
+}
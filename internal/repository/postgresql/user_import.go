@@ -0,0 +1,154 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/jackc/pgx/v5"
+)
+
+// Import creates a batch of users with their referrers and opening balances in
+// the currency. Rows whose username is taken or whose referrer can't be found are
+// skipped and returned as row errors, the rest of the batch is imported
+func (r *userRepository) Import(ctx context.Context, rows []*entities.ImportRow, currency string) (int, []entities.ImportRowError, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx) // Rollback on error
+	}()
+
+	_, err = tx.Exec(ctx, `
+		CREATE TEMP TABLE import_users (
+			line INT NOT NULL,
+			username VARCHAR(255) NOT NULL,
+			username_normalized VARCHAR(255) NOT NULL,
+			referrer_normalized VARCHAR(255),
+			balance BIGINT NOT NULL
+		) ON COMMIT DROP`)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create import table: %w", err)
+	}
+
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"import_users"},
+		[]string{"line", "username", "username_normalized", "referrer_normalized", "balance"},
+		pgx.CopyFromSlice(len(rows), func(i int) ([]any, error) {
+			row := rows[i]
+			var referrer *string
+			if row.ReferrerUsername != "" {
+				normalized := entities.NormalizeUsername(row.ReferrerUsername)
+				referrer = &normalized
+			}
+			return []any{row.Line, row.Username, entities.NormalizeUsername(row.Username), referrer, row.Balance}, nil
+		}),
+	)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to copy import rows: %w", err)
+	}
+
+	// Drop rows taken by existing users
+	rowErrors, err := deleteImportRows(ctx, tx, "username is taken", `
+		DELETE FROM import_users i
+		USING users u
+		WHERE u.username_normalized = i.username_normalized
+		RETURNING i.line, i.username`)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// Drop rows whose referrer is neither an existing user nor an earlier row,
+	// until dropped rows leave no referrals dangling
+	for {
+		missing, err := deleteImportRows(ctx, tx, "referrer not found", `
+			DELETE FROM import_users i
+			WHERE i.referrer_normalized IS NOT NULL
+			  AND NOT EXISTS (
+				SELECT 1 FROM users u
+				WHERE u.username_normalized = i.referrer_normalized AND u.deleted_at IS NULL
+			  )
+			  AND NOT EXISTS (
+				SELECT 1 FROM import_users j
+				WHERE j.username_normalized = i.referrer_normalized AND j.line < i.line
+			  )
+			RETURNING i.line, i.username`)
+		if err != nil {
+			return 0, nil, err
+		}
+		if len(missing) == 0 {
+			break
+		}
+		rowErrors = append(rowErrors, missing...)
+	}
+
+	// Balances in the default currency are created by trigger
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO users (username, username_normalized)
+		SELECT username, username_normalized FROM import_users ORDER BY line`)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to insert users: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE users u
+		SET referrer_id = r.id
+		FROM import_users i
+		JOIN users r ON r.username_normalized = i.referrer_normalized
+		WHERE u.username_normalized = i.username_normalized`)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to set referrers: %w", err)
+	}
+
+	// Opening balances count towards lifetime points like any earned points
+	_, err = tx.Exec(ctx, `
+		INSERT INTO balances (user_id, currency, points, lifetime_points, updated_at)
+		SELECT u.id, $1, i.balance, i.balance, CURRENT_TIMESTAMP
+		FROM import_users i
+		JOIN users u ON u.username_normalized = i.username_normalized
+		WHERE i.balance > 0
+		ON CONFLICT (user_id, currency) DO UPDATE
+		SET points = balances.points + EXCLUDED.points,
+		    lifetime_points = balances.lifetime_points + EXCLUDED.lifetime_points,
+		    updated_at = EXCLUDED.updated_at`, currency)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to set opening balances: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO transactions (user_id, currency, delta, reason, reference_type, created_at)
+		SELECT u.id, $1, i.balance, 'Opening balance', $2, CURRENT_TIMESTAMP
+		FROM import_users i
+		JOIN users u ON u.username_normalized = i.username_normalized
+		WHERE i.balance > 0`, currency, entities.ImportReferenceType)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to record opening balances: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, nil, err
+	}
+	return int(tag.RowsAffected()), rowErrors, nil
+}
+
+// deleteImportRows runs a DELETE ... RETURNING line, username on the import
+// table and reports the deleted rows with the message
+func deleteImportRows(ctx context.Context, tx pgx.Tx, message, query string) ([]entities.ImportRowError, error) {
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check import rows: %w", err)
+	}
+	defer rows.Close()
+
+	var rowErrors []entities.ImportRowError
+	for rows.Next() {
+		rowErr := entities.ImportRowError{Error: message}
+		if err := rows.Scan(&rowErr.Line, &rowErr.Username); err != nil {
+			return nil, err
+		}
+		rowErrors = append(rowErrors, rowErr)
+	}
+
+	return rowErrors, rows.Err()
+}
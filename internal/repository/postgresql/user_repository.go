@@ -2,15 +2,21 @@ package postgresql
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/abdullinmm/user-management-api/internal/domain/entities"
 	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// uniqueViolation is the PostgreSQL error code of unique constraint violations
+const uniqueViolation = "23505"
+
 type userRepository struct {
 	db *pgxpool.Pool
 }
@@ -23,33 +29,63 @@ func NewUserRepository(db *pgxpool.Pool) interfaces.UserRepository {
 // Create creates a new user
 func (r *userRepository) Create(ctx context.Context, user *entities.User) error {
 	query := `
-        INSERT INTO users (username, referrer_id, created_at)
-        VALUES ($1, $2, $3)
-        RETURNING id`
+        INSERT INTO users (username, username_normalized, referrer_id, created_at)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, role`
 
-	err := r.db.QueryRow(ctx, query, user.Username, user.ReferrerID, user.CreatedAt).Scan(&user.ID)
+	err := r.db.QueryRow(ctx, query, user.Username, user.NormalizedUsername, user.ReferrerID, user.CreatedAt).Scan(&user.ID, &user.Role)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return &entities.ProfileFieldTakenError{Field: "username", Value: user.Username}
+		}
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 
 	return nil
 }
 
-// GetByID gets user by ID
-func (r *userRepository) GetByID(ctx context.Context, id int64) (*entities.User, error) {
-	query := `
-		SELECT id, username, referrer_id, created_at
-        FROM users
-        WHERE id = $1`
+// userColumns lists user columns in the order expected by scanUser
+const userColumns = `id, username, username_normalized, referrer_id, role, created_at, updated_at,
+		display_name, email, avatar_url, locale, timezone, attributes,
+		status, status_reason, status_until, deleted_at`
 
+// scanUser scans a row of userColumns followed by extra destinations into a user
+func scanUser(row pgx.Row, extra ...any) (*entities.User, error) {
 	user := &entities.User{}
-	err := r.db.QueryRow(ctx, query, id).Scan(
+	dest := []any{
 		&user.ID,
 		&user.Username,
+		&user.NormalizedUsername,
 		&user.ReferrerID,
+		&user.Role,
 		&user.CreatedAt,
-	)
+		&user.UpdatedAt,
+		&user.DisplayName,
+		&user.Email,
+		&user.AvatarURL,
+		&user.Locale,
+		&user.Timezone,
+		&user.Attributes,
+		&user.Status,
+		&user.StatusReason,
+		&user.StatusUntil,
+		&user.DeletedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
 
+// GetByID gets user by ID
+func (r *userRepository) GetByID(ctx context.Context, id int64) (*entities.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+        FROM users
+        WHERE id = $1`
+
+	user, err := scanUser(reader(ctx, r.db).QueryRow(ctx, query, id))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil // User not found, return nil instead of error
@@ -62,18 +98,28 @@ func (r *userRepository) GetByID(ctx context.Context, id int64) (*entities.User,
 // GetByUsername gets user by username
 func (r *userRepository) GetByUsername(ctx context.Context, username string) (*entities.User, error) {
 	query := `
-		SELECT id, username, referrer_id, created_at
+		SELECT ` + userColumns + `
         FROM users
         WHERE username = $1`
 
-	user := &entities.User{}
-	err := r.db.QueryRow(ctx, query, username).Scan(
-		&user.ID,
-		&user.Username,
-		&user.ReferrerID,
-		&user.CreatedAt,
-	)
+	user, err := scanUser(r.db.QueryRow(ctx, query, username))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // User not found
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
+
+// GetByNormalizedUsername gets the user whose username has the given normalized form
+func (r *userRepository) GetByNormalizedUsername(ctx context.Context, normalized string) (*entities.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+        FROM users
+        WHERE username_normalized = $1`
 
+	user, err := scanUser(r.db.QueryRow(ctx, query, normalized))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil // User not found
@@ -83,6 +129,377 @@ func (r *userRepository) GetByUsername(ctx context.Context, username string) (*e
 	return user, nil
 }
 
+// UpdateProfile locks the user, applies update to it and stores the username and
+// profile, recording the previous username when it changes
+func (r *userRepository) UpdateProfile(ctx context.Context, id int64, update func(user *entities.User) error) (*entities.User, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx) // Rollback on error
+	}()
+
+	user, err := scanUser(tx.QueryRow(ctx, `
+		SELECT `+userColumns+`
+		FROM users
+		WHERE id = $1
+		FOR UPDATE`, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, &entities.UserNotFoundError{ID: id}
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	previousUsername := user.Username
+	if err := update(user); err != nil {
+		return nil, err
+	}
+
+	if user.Username != previousUsername {
+		_, err := tx.Exec(ctx, `INSERT INTO username_history (user_id, username) VALUES ($1, $2)`, id, previousUsername)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record username change: %w", err)
+		}
+	}
+
+	query := `
+		UPDATE users
+		SET username = $2, username_normalized = $3, display_name = $4, email = $5, avatar_url = $6,
+		    locale = $7, timezone = $8, attributes = $9, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING updated_at`
+
+	err = tx.QueryRow(
+		ctx,
+		query,
+		id,
+		user.Username,
+		user.NormalizedUsername,
+		user.DisplayName,
+		user.Email,
+		user.AvatarURL,
+		user.Locale,
+		user.Timezone,
+		user.Attributes,
+	).Scan(&user.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			if pgErr.ConstraintName == "idx_users_email" {
+				return nil, &entities.ProfileFieldTakenError{Field: "email", Value: *user.Email}
+			}
+			return nil, &entities.ProfileFieldTakenError{Field: "username", Value: user.Username}
+		}
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// SetStatus suspends, bans or reactivates a user and records the change with the
+// previous status in the audit log
+func (r *userRepository) SetStatus(ctx context.Context, change *entities.UserStatusChange) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx) // Rollback on error
+	}()
+
+	err = tx.QueryRow(ctx, `SELECT status FROM users WHERE id = $1 FOR UPDATE`, change.UserID).Scan(&change.PreviousStatus)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return &entities.UserNotFoundError{ID: change.UserID}
+		}
+		return fmt.Errorf("failed to get user status: %w", err)
+	}
+
+	var reason *string
+	if change.Status != entities.UserStatusActive {
+		reason = &change.Reason
+	}
+	_, err = tx.Exec(ctx, `
+		UPDATE users
+		SET status = $2, status_reason = $3, status_until = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`, change.UserID, change.Status, reason, change.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to update user status: %w", err)
+	}
+
+	query := `
+		INSERT INTO user_status_changes (user_id, actor_id, status, previous_status, reason, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+
+	err = tx.QueryRow(
+		ctx,
+		query,
+		change.UserID,
+		change.ActorID,
+		change.Status,
+		change.PreviousStatus,
+		change.Reason,
+		change.ExpiresAt,
+	).Scan(&change.ID, &change.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetStatusChanges gets the status change audit of a user, latest first
+func (r *userRepository) GetStatusChanges(ctx context.Context, userID int64) ([]*entities.UserStatusChange, error) {
+	query := `
+		SELECT id, user_id, actor_id, status, previous_status, reason, expires_at, created_at
+		FROM user_status_changes
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*entities.UserStatusChange
+	for rows.Next() {
+		var change entities.UserStatusChange
+		err := rows.Scan(
+			&change.ID,
+			&change.UserID,
+			&change.ActorID,
+			&change.Status,
+			&change.PreviousStatus,
+			&change.Reason,
+			&change.ExpiresAt,
+			&change.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, &change)
+	}
+
+	return changes, rows.Err()
+}
+
+// SoftDelete marks a user as deleted and returns the deletion time, deleting
+// a deleted user again keeps the original time
+func (r *userRepository) SoftDelete(ctx context.Context, id int64) (time.Time, error) {
+	query := `
+		UPDATE users
+		SET deleted_at = COALESCE(deleted_at, now())
+		WHERE id = $1 AND anonymized_at IS NULL
+		RETURNING deleted_at`
+
+	var deletedAt time.Time
+	if err := r.db.QueryRow(ctx, query, id).Scan(&deletedAt); err != nil {
+		if err == pgx.ErrNoRows {
+			return time.Time{}, &entities.UserNotFoundError{ID: id}
+		}
+		return time.Time{}, fmt.Errorf("failed to delete user: %w", err)
+	}
+	return deletedAt, nil
+}
+
+// Restore undeletes a user that isn't anonymised yet and reports whether the user was deleted
+func (r *userRepository) Restore(ctx context.Context, id int64) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE users SET deleted_at = NULL
+		WHERE id = $1 AND deleted_at IS NOT NULL AND anonymized_at IS NULL`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to restore user: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetDeletedBefore gets IDs of users deleted before the given time that aren't anonymised yet
+func (r *userRepository) GetDeletedBefore(ctx context.Context, before time.Time, limit int) ([]int64, error) {
+	query := `
+		SELECT id FROM users
+		WHERE deleted_at <= $1 AND anonymized_at IS NULL
+		ORDER BY deleted_at
+		LIMIT $2`
+
+	rows, err := r.db.Query(ctx, query, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted users: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// Anonymize scrubs the username, profile, username history, task proofs and moderation
+// notes of a user, keeps balances and transactions linked to the user ID and stores a
+// deletion receipt
+func (r *userRepository) Anonymize(ctx context.Context, id int64, requestedBy *int64) (*entities.DeletionReceipt, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx) // Rollback on error
+	}()
+
+	receipt := &entities.DeletionReceipt{
+		UserID:       id,
+		RequestedBy:  requestedBy,
+		ErasedFields: entities.ErasedFields,
+	}
+
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(deleted_at, now()) FROM users
+		WHERE id = $1 AND anonymized_at IS NULL
+		FOR UPDATE`, id).Scan(&receipt.RequestedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, &entities.UserNotFoundError{ID: id}
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM username_history WHERE user_id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete username history: %w", err)
+	}
+	receipt.UsernameChangesDeleted = tag.RowsAffected()
+
+	tag, err = tx.Exec(ctx, `
+		UPDATE user_tasks SET proof_url = NULL, proof_text = NULL, review_note = NULL
+		WHERE user_id = $1 AND (proof_url IS NOT NULL OR proof_text IS NOT NULL OR review_note IS NOT NULL)`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrub task proofs: %w", err)
+	}
+	receipt.ProofsScrubbed = tag.RowsAffected()
+
+	// Status changes stay in the audit without the moderators' reasons
+	_, err = tx.Exec(ctx, `UPDATE user_status_changes SET reason = '' WHERE user_id = $1 AND reason <> ''`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrub status change reasons: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM data_exports WHERE user_id = $1`, id); err != nil {
+		return nil, fmt.Errorf("failed to delete data exports: %w", err)
+	}
+
+	err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM transactions WHERE user_id = $1`, id).Scan(&receipt.TransactionsRetained)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count transactions: %w", err)
+	}
+
+	username := entities.AnonymizedUsername(id)
+	_, err = tx.Exec(ctx, `
+		UPDATE users
+		SET username = $2, username_normalized = $2, display_name = NULL, email = NULL, avatar_url = NULL,
+		    locale = NULL, timezone = NULL, attributes = '{}', status_reason = NULL,
+		    deleted_at = COALESCE(deleted_at, now()),
+		    anonymized_at = now(), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`, id, username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to anonymize user: %w", err)
+	}
+
+	query := `
+		INSERT INTO deletion_receipts (user_id, requested_by, requested_at, erased_fields,
+		                               username_changes_deleted, proofs_scrubbed, transactions_retained)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, erased_at`
+
+	err = tx.QueryRow(
+		ctx,
+		query,
+		receipt.UserID,
+		receipt.RequestedBy,
+		receipt.RequestedAt,
+		receipt.ErasedFields,
+		receipt.UsernameChangesDeleted,
+		receipt.ProofsScrubbed,
+		receipt.TransactionsRetained,
+	).Scan(&receipt.ID, &receipt.ErasedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store deletion receipt: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// GetDeletionReceipt gets the deletion receipt of an anonymised user
+func (r *userRepository) GetDeletionReceipt(ctx context.Context, userID int64) (*entities.DeletionReceipt, error) {
+	query := `
+		SELECT id, user_id, requested_by, requested_at, erased_at, erased_fields,
+		       username_changes_deleted, proofs_scrubbed, transactions_retained
+		FROM deletion_receipts
+		WHERE user_id = $1`
+
+	var receipt entities.DeletionReceipt
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&receipt.ID,
+		&receipt.UserID,
+		&receipt.RequestedBy,
+		&receipt.RequestedAt,
+		&receipt.ErasedAt,
+		&receipt.ErasedFields,
+		&receipt.UsernameChangesDeleted,
+		&receipt.ProofsScrubbed,
+		&receipt.TransactionsRetained,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // User not anonymised
+		}
+		return nil, fmt.Errorf("failed to get deletion receipt: %w", err)
+	}
+	return &receipt, nil
+}
+
+// GetUsernameHistory gets previous usernames of a user, latest first
+func (r *userRepository) GetUsernameHistory(ctx context.Context, userID int64) ([]*entities.UsernameChange, error) {
+	query := `
+		SELECT user_id, username, changed_at
+		FROM username_history
+		WHERE user_id = $1
+		ORDER BY changed_at DESC, id DESC`
+
+	rows, err := reader(ctx, r.db).Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get username history: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*entities.UsernameChange
+	for rows.Next() {
+		var change entities.UsernameChange
+		if err := rows.Scan(&change.UserID, &change.Username, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, &change)
+	}
+
+	return changes, rows.Err()
+}
+
 // GetWithReferrals gets user with referral information
 func (r *userRepository) GetWithReferrals(ctx context.Context, id int64) (*entities.UserWithReferrals, error) {
 	query := `
@@ -111,6 +528,32 @@ func (r *userRepository) GetWithReferrals(ctx context.Context, id int64) (*entit
 	return userWithRefs, nil
 }
 
+// GetReferrals gets users invited by the referrer, oldest first
+func (r *userRepository) GetReferrals(ctx context.Context, referrerID int64) ([]*entities.Referral, error) {
+	query := `
+		SELECT id, username, created_at
+		FROM users
+		WHERE referrer_id = $1
+		ORDER BY created_at, id`
+
+	rows, err := reader(ctx, r.db).Query(ctx, query, referrerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get referrals: %w", err)
+	}
+	defer rows.Close()
+
+	var referrals []*entities.Referral
+	for rows.Next() {
+		var referral entities.Referral
+		if err := rows.Scan(&referral.UserID, &referral.Username, &referral.CreatedAt); err != nil {
+			return nil, err
+		}
+		referrals = append(referrals, &referral)
+	}
+
+	return referrals, rows.Err()
+}
+
 // SetReferrer sets referrer for a user
 func (r *userRepository) SetReferrer(ctx context.Context, userID, referrerID int64) error {
 	query := `
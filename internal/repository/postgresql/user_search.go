@@ -0,0 +1,124 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+)
+
+// userSortColumns maps a search sort field to its expression in the search
+// query, its result column and the type cursor values are cast to
+var userSortColumns = map[string]struct {
+	expr, column, cast string
+}{
+	entities.UserSortCreatedAt: {"u.created_at", "created_at", "timestamp"},
+	entities.UserSortUsername:  {"u.username", "username", "text"},
+	entities.UserSortBalance:   {"COALESCE(b.points, 0)", "balance", "bigint"},
+	entities.UserSortReferrals: {"rc.referral_count", "referral_count", "bigint"},
+}
+
+// likeEscaper escapes LIKE wildcards, '_' is a username separator
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Search finds users matching the search with their balance in search.Currency and
+// referral counts. One row more than the limit is returned when there is a next page
+func (r *userRepository) Search(ctx context.Context, search *entities.UserSearch) ([]*entities.UserSearchResult, error) {
+	sort, ok := userSortColumns[search.Sort]
+	if !ok {
+		return nil, &entities.InvalidUserSearchError{Reason: "unknown sort " + search.Sort}
+	}
+
+	args := []any{search.Currency}
+	arg := func(value any) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	var conditions []string
+	if query := search.NormalizedQuery(); query != "" {
+		pattern := likeEscaper.Replace(query) + "%"
+		if search.Match == entities.UserMatchSubstring {
+			pattern = "%" + pattern
+		}
+		// Served by the trigram index on username_normalized
+		conditions = append(conditions, "u.username_normalized LIKE "+arg(pattern))
+	}
+	if search.CreatedFrom != nil {
+		conditions = append(conditions, "u.created_at >= "+arg(*search.CreatedFrom))
+	}
+	if search.CreatedTo != nil {
+		conditions = append(conditions, "u.created_at <= "+arg(*search.CreatedTo))
+	}
+	if search.HasReferrer != nil {
+		conditions = append(conditions, "(u.referrer_id IS NOT NULL) = "+arg(*search.HasReferrer))
+	}
+	if search.MinBalance != nil {
+		conditions = append(conditions, "COALESCE(b.points, 0) >= "+arg(*search.MinBalance))
+	}
+	if search.MaxBalance != nil {
+		conditions = append(conditions, "COALESCE(b.points, 0) <= "+arg(*search.MaxBalance))
+	}
+
+	switch search.Status {
+	case entities.UserStatusActive:
+		conditions = append(conditions, activeUserCondition)
+	case entities.UserStatusSuspended, entities.UserStatusBanned:
+		conditions = append(conditions, "u.deleted_at IS NULL AND u.status = "+arg(search.Status)+
+			" AND (u.status_until IS NULL OR u.status_until > now())")
+	case entities.UserStatusDeleted:
+		conditions = append(conditions, "u.deleted_at IS NOT NULL")
+	}
+
+	order, compare := "ASC", ">"
+	if search.Descending {
+		order, compare = "DESC", "<"
+	}
+
+	// Keyset pagination: continue after the (sort value, id) of the cursor
+	if search.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(%s, u.id) %s (%s::%s, %s)",
+			sort.expr, compare, arg(search.After.Value), sort.cast, arg(search.After.ID)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, "\n\t\t\t  AND ")
+	}
+
+	query := `
+		SELECT ` + userColumns + `, balance, referral_count
+		FROM (
+			SELECT u.*, COALESCE(b.points, 0) AS balance, rc.referral_count
+			FROM users u
+			LEFT JOIN balances b ON b.user_id = u.id AND b.currency = $1
+			CROSS JOIN LATERAL (
+				SELECT COUNT(*) AS referral_count FROM users r WHERE r.referrer_id = u.id
+			) rc
+			` + where + `
+			ORDER BY ` + sort.expr + ` ` + order + `, u.id ` + order + `
+			LIMIT ` + arg(search.Limit+1) + `
+		) s
+		ORDER BY ` + sort.column + ` ` + order + `, id ` + order
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*entities.UserSearchResult
+	for rows.Next() {
+		var balance, referralCount int64
+		user, err := scanUser(rows, &balance, &referralCount)
+		if err != nil {
+			return nil, err
+		}
+		user.Balance = balance
+		results = append(results, &entities.UserSearchResult{User: *user, ReferralCount: referralCount})
+	}
+
+	return results, rows.Err()
+}
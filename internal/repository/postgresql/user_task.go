@@ -2,43 +2,163 @@ package postgresql
 
 import (
 	"context"
+	"time"
 
 	"github.com/abdullinmm/user-management-api/internal/domain/entities"
 	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // UserTaskRepository handles user task completion operations
 type UserTaskRepository struct {
 	db *pgxpool.Pool
+	// timezone defines day and week boundaries of recurring tasks
+	timezone string
 }
 
 // NewUserTaskRepository creates a new user task repository
-func NewUserTaskRepository(db *pgxpool.Pool) interfaces.UserTaskRepository {
-	return &UserTaskRepository{db: db}
+func NewUserTaskRepository(db *pgxpool.Pool, location *time.Location) interfaces.UserTaskRepository {
+	return &UserTaskRepository{db: db, timezone: location.String()}
 }
 
-// Create records a task completion for a user
+// Create records a task completion (or a pending submission) for a user in the task's
+// current recurrence period, replacing a rejected submission of the period.
+// The task row is locked so that the availability window and completion caps are
+// enforced under concurrent completions
 func (r *UserTaskRepository) Create(ctx context.Context, userTask *entities.UserTask) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx) // Rollback on error
+	}()
+
+	task, err := scanTask(tx.QueryRow(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks t
+		WHERE t.id = $1
+		FOR UPDATE`, userTask.TaskID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return &entities.TaskNotFoundError{ID: userTask.TaskID}
+		}
+		return err
+	}
+
+	if err := task.CheckAvailable(userTask.CompletedAt); err != nil {
+		return err
+	}
+
+	if task.MaxPerUser != nil {
+		var completions int
+		err := tx.QueryRow(ctx, `
+			SELECT COUNT(*) FROM user_tasks
+			WHERE user_id = $1 AND task_id = $2 AND status <> 'rejected'`, userTask.UserID, userTask.TaskID).Scan(&completions)
+		if err != nil {
+			return err
+		}
+		if err := task.CheckUserLimit(userTask.UserID, completions); err != nil {
+			return err
+		}
+	}
+
+	if userTask.Status == "" {
+		userTask.Status = entities.UserTaskStatusCompleted
+	}
+
 	query := `
-		INSERT INTO user_tasks (user_id, task_id, completed_at)
-		VALUES ($1, $2, $3)`
+		INSERT INTO user_tasks (user_id, task_id, completed_at, period_start, status, proof_url, proof_text, task_version)
+		SELECT $1, t.id, $3, task_period_start(t.recurrence, t.recurrence_hours, now(), $4), $5, $6, $7, t.version
+		FROM tasks t
+		WHERE t.id = $2
+		ON CONFLICT (user_id, task_id, period_start) DO UPDATE
+		SET completed_at = EXCLUDED.completed_at,
+		    status = EXCLUDED.status,
+		    task_version = EXCLUDED.task_version,
+		    proof_url = EXCLUDED.proof_url,
+		    proof_text = EXCLUDED.proof_text,
+		    reviewed_by = NULL,
+		    reviewed_at = NULL,
+		    review_note = NULL
+		WHERE user_tasks.status = 'rejected'
+		RETURNING id, period_start, task_version`
 
-	_, err := r.db.Exec(ctx, query, userTask.UserID, userTask.TaskID, userTask.CompletedAt)
-	return err
+	err = tx.QueryRow(
+		ctx,
+		query,
+		userTask.UserID,
+		userTask.TaskID,
+		userTask.CompletedAt,
+		r.timezone,
+		userTask.Status,
+		userTask.ProofURL,
+		userTask.ProofText,
+	).Scan(&userTask.ID, &userTask.PeriodStart, &userTask.TaskVersion)
+	if err == pgx.ErrNoRows {
+		// Completion or pending submission for the current period already exists
+		return &entities.TaskAlreadyCompletedError{UserID: userTask.UserID, TaskID: userTask.TaskID}
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE tasks SET completions = completions + 1 WHERE id = $1`, userTask.TaskID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetForCurrentPeriod retrieves the user's completion or submission of a task in its
+// current recurrence period
+func (r *UserTaskRepository) GetForCurrentPeriod(ctx context.Context, userID, taskID int64) (*entities.UserTask, error) {
+	query := `
+		SELECT ut.id, ut.user_id, ut.task_id, ut.completed_at, ut.period_start, ut.task_version, ut.reward_points,
+		       ut.status, ut.proof_url, ut.proof_text
+		FROM user_tasks ut
+		JOIN tasks t ON ut.task_id = t.id
+		WHERE ut.user_id = $1 AND ut.task_id = $2
+		  AND ut.period_start = task_period_start(t.recurrence, t.recurrence_hours, now(), $3)`
+
+	var userTask entities.UserTask
+	err := r.db.QueryRow(ctx, query, userID, taskID, r.timezone).Scan(
+		&userTask.ID,
+		&userTask.UserID,
+		&userTask.TaskID,
+		&userTask.CompletedAt,
+		&userTask.PeriodStart,
+		&userTask.TaskVersion,
+		&userTask.RewardPoints,
+		&userTask.Status,
+		&userTask.ProofURL,
+		&userTask.ProofText,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // Not completed in the current period
+		}
+		return nil, err
+	}
+
+	return &userTask, nil
 }
 
-// IsCompleted checks if a user has already completed a specific task
+// IsCompleted checks if a user has already completed a task, or submitted it for
+// verification, in its current recurrence period
 func (r *UserTaskRepository) IsCompleted(ctx context.Context, userID int64, taskID int64) (bool, error) {
 	query := `
 		SELECT EXISTS(
-			SELECT 1 FROM user_tasks
-			WHERE user_id = $1 AND task_id = $2
+			SELECT 1 FROM user_tasks ut
+			JOIN tasks t ON ut.task_id = t.id
+			WHERE ut.user_id = $1 AND ut.task_id = $2 AND ut.status <> 'rejected'
+			  AND ut.period_start = task_period_start(t.recurrence, t.recurrence_hours, now(), $3)
 		)`
 
 	var exists bool
-	err := r.db.QueryRow(ctx, query, userID, taskID).Scan(&exists)
+	err := r.db.QueryRow(ctx, query, userID, taskID, r.timezone).Scan(&exists)
 	if err != nil {
 		return false, err
 	}
@@ -46,22 +166,48 @@ func (r *UserTaskRepository) IsCompleted(ctx context.Context, userID int64, task
 	return exists, nil
 }
 
-// GetByUserID retrieves all completed tasks for a user with task details
-func (r *UserTaskRepository) GetByUserID(ctx context.Context, userID int64) ([]*entities.UserTaskWithDetails, error) {
-	query := `
-		SELECT
-			ut.user_id,
-			ut.task_id,
-			t.code as task_code,
-			t.title as task_title,
-			t.reward_points,
-			ut.completed_at
-		FROM user_tasks ut
+// userTaskDetailsColumns lists completion columns in the order expected by scanUserTaskDetails,
+// selected from userTaskDetailsTables
+const userTaskDetailsColumns = `ut.id, ut.user_id, ut.task_id, t.code, t.title, t.recurrence,
+		COALESCE(ut.reward_points, tv.reward_points, t.reward_points), ut.task_version,
+		ut.completed_at, ut.period_start, ut.status, ut.proof_url, ut.proof_text,
+		ut.reviewed_by, ut.reviewed_at, ut.review_note`
+
+// userTaskDetailsTables joins completions with their tasks and completed task versions
+const userTaskDetailsTables = `user_tasks ut
 		JOIN tasks t ON ut.task_id = t.id
-		WHERE ut.user_id = $1
-		ORDER BY ut.completed_at DESC`
+		LEFT JOIN task_versions tv ON tv.task_id = ut.task_id AND tv.version = ut.task_version`
 
-	rows, err := r.db.Query(ctx, query, userID)
+// scanUserTaskDetails scans a row of userTaskDetailsColumns into a completion
+func scanUserTaskDetails(row pgx.Row) (*entities.UserTaskWithDetails, error) {
+	var ut entities.UserTaskWithDetails
+	err := row.Scan(
+		&ut.ID,
+		&ut.UserID,
+		&ut.TaskID,
+		&ut.TaskCode,
+		&ut.TaskTitle,
+		&ut.Recurrence,
+		&ut.RewardPoints,
+		&ut.TaskVersion,
+		&ut.CompletedAt,
+		&ut.PeriodStart,
+		&ut.Status,
+		&ut.ProofURL,
+		&ut.ProofText,
+		&ut.ReviewedBy,
+		&ut.ReviewedAt,
+		&ut.ReviewNote,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &ut, nil
+}
+
+// queryUserTaskDetails runs a query selecting userTaskDetailsColumns and loads earned rewards
+func (r *UserTaskRepository) queryUserTaskDetails(ctx context.Context, query string, args ...any) ([]*entities.UserTaskWithDetails, error) {
+	rows, err := reader(ctx, r.db).Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -69,35 +215,233 @@ func (r *UserTaskRepository) GetByUserID(ctx context.Context, userID int64) ([]*
 
 	var userTasks []*entities.UserTaskWithDetails
 	for rows.Next() {
-		var ut entities.UserTaskWithDetails
-		err := rows.Scan(
-			&ut.UserID,
-			&ut.TaskID,
-			&ut.TaskCode,
-			&ut.TaskTitle,
-			&ut.RewardPoints,
-			&ut.CompletedAt,
-		)
+		ut, err := scanUserTaskDetails(rows)
 		if err != nil {
 			return nil, err
 		}
-		userTasks = append(userTasks, &ut)
+		userTasks = append(userTasks, ut)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadEarnedRewards(ctx, userTasks); err != nil {
+		return nil, err
 	}
 
-	return userTasks, rows.Err()
+	return userTasks, nil
+}
+
+// GetByUserID retrieves the completion history of a user with task details, one row per
+// period, including pending and rejected submissions
+func (r *UserTaskRepository) GetByUserID(ctx context.Context, userID int64) ([]*entities.UserTaskWithDetails, error) {
+	query := `
+		SELECT ` + userTaskDetailsColumns + `
+		FROM ` + userTaskDetailsTables + `
+		WHERE ut.user_id = $1
+		ORDER BY ut.completed_at DESC`
+
+	return r.queryUserTaskDetails(ctx, query, userID)
 }
 
-// GetAvailableTasksForUser retrieves all active tasks that the user has not completed yet
-func (r *UserTaskRepository) GetAvailableTasksForUser(ctx context.Context, userID int64) ([]*entities.Task, error) {
+// GetByID retrieves a completion or submission with task details
+func (r *UserTaskRepository) GetByID(ctx context.Context, id int64) (*entities.UserTaskWithDetails, error) {
 	query := `
-		SELECT t.id, t.code, t.title, t.reward_points, t.is_active, t.created_at
+		SELECT ` + userTaskDetailsColumns + `
+		FROM ` + userTaskDetailsTables + `
+		WHERE ut.id = $1`
+
+	userTasks, err := r.queryUserTaskDetails(ctx, query, id)
+	if err != nil || len(userTasks) == 0 {
+		return nil, err // Submission not found
+	}
+
+	return userTasks[0], nil
+}
+
+// GetPending retrieves submissions awaiting verification, oldest first
+func (r *UserTaskRepository) GetPending(ctx context.Context, limit, offset int) ([]*entities.UserTaskWithDetails, error) {
+	query := `
+		SELECT ` + userTaskDetailsColumns + `
+		FROM ` + userTaskDetailsTables + `
+		WHERE ut.status = 'pending'
+		ORDER BY ut.completed_at ASC, ut.id ASC
+		LIMIT $1 OFFSET $2`
+
+	return r.queryUserTaskDetails(ctx, query, limit, offset)
+}
+
+// Review sets the status of a pending submission to completed or rejected and returns it.
+// A rejected submission releases its slot under the task's completion caps
+func (r *UserTaskRepository) Review(ctx context.Context, id int64, status string, reviewerID *int64, note *string) (*entities.UserTask, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx) // Rollback on error
+	}()
+
+	query := `
+		UPDATE user_tasks
+		SET status = $2, reviewed_by = $3, reviewed_at = CURRENT_TIMESTAMP, review_note = $4
+		WHERE id = $1 AND status = 'pending'
+		RETURNING id, user_id, task_id, completed_at, period_start, task_version, reward_points, status, proof_url, proof_text`
+
+	var userTask entities.UserTask
+	err = tx.QueryRow(ctx, query, id, status, reviewerID, note).Scan(
+		&userTask.ID,
+		&userTask.UserID,
+		&userTask.TaskID,
+		&userTask.CompletedAt,
+		&userTask.PeriodStart,
+		&userTask.TaskVersion,
+		&userTask.RewardPoints,
+		&userTask.Status,
+		&userTask.ProofURL,
+		&userTask.ProofText,
+	)
+	if err == pgx.ErrNoRows {
+		// Either submission doesn't exist or it was already reviewed
+		var current string
+		err := tx.QueryRow(ctx, `SELECT status FROM user_tasks WHERE id = $1`, id).Scan(&current)
+		if err == pgx.ErrNoRows {
+			return nil, &entities.SubmissionNotFoundError{ID: id}
+		}
+		if err != nil {
+			return nil, err
+		}
+		return nil, &entities.SubmissionNotPendingError{ID: id, Status: current}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if status == entities.UserTaskStatusRejected {
+		if _, err := tx.Exec(ctx, `UPDATE tasks SET completions = completions - 1 WHERE id = $1`, userTask.TaskID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &userTask, nil
+}
+
+// Reopen returns an approved submission to the moderation queue
+func (r *UserTaskRepository) Reopen(ctx context.Context, id int64) error {
+	query := `
+		UPDATE user_tasks
+		SET status = 'pending', reviewed_by = NULL, reviewed_at = NULL, review_note = NULL, reward_points = NULL
+		WHERE id = $1 AND status = 'completed'`
+
+	_, err := r.db.Exec(ctx, query, id)
+	return err
+}
+
+// SetRewardPoints stores default currency points awarded for a completion
+func (r *UserTaskRepository) SetRewardPoints(ctx context.Context, id, points int64) error {
+	_, err := r.db.Exec(ctx, `UPDATE user_tasks SET reward_points = $2 WHERE id = $1`, id, points)
+	return err
+}
+
+// loadEarnedRewards fills rewards earned by the given completions from linked transactions
+func (r *UserTaskRepository) loadEarnedRewards(ctx context.Context, userTasks []*entities.UserTaskWithDetails) error {
+	if len(userTasks) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(userTasks))
+	byID := make(map[int64]*entities.UserTaskWithDetails, len(userTasks))
+	for _, ut := range userTasks {
+		ut.Rewards = []entities.TaskReward{}
+		ids = append(ids, ut.ID)
+		byID[ut.ID] = ut
+	}
+
+	query := `
+		SELECT user_task_id, currency, SUM(delta)
+		FROM transactions
+		WHERE user_task_id = ANY($1)
+		GROUP BY user_task_id, currency
+		ORDER BY user_task_id, currency`
+
+	rows, err := reader(ctx, r.db).Query(ctx, query, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userTaskID int64
+		var reward entities.TaskReward
+		if err := rows.Scan(&userTaskID, &reward.Currency, &reward.Amount); err != nil {
+			return err
+		}
+		ut := byID[userTaskID]
+		reward.TaskID = ut.TaskID
+		ut.Rewards = append(ut.Rewards, reward)
+	}
+
+	return rows.Err()
+}
+
+// GetAvailableTasksForUser retrieves all active unlocked tasks targeted at the audience within
+// their availability window and completion caps that the user has not completed in their
+// current recurrence period
+func (r *UserTaskRepository) GetAvailableTasksForUser(ctx context.Context, audience *entities.Audience) ([]*entities.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
 		FROM tasks t
+		JOIN users u ON u.id = $1
+		LEFT JOIN task_categories c ON c.id = t.category_id
 		WHERE t.is_active = true
+		  AND (t.min_level IS NULL OR t.min_level <= $3)
+		  AND (t.min_points IS NULL OR t.min_points <= $4)
+		  AND (t.min_account_age_days IS NULL OR u.created_at <= CURRENT_TIMESTAMP - make_interval(days => t.min_account_age_days))
+		  AND (t.has_referrer IS NULL OR t.has_referrer = (u.referrer_id IS NOT NULL))
+		  AND (
+			NOT EXISTS (SELECT 1 FROM task_segments ts WHERE ts.task_id = t.id)
+			OR EXISTS (
+				SELECT 1 FROM task_segments ts
+				JOIN user_segment_members m ON m.segment_id = ts.segment_id
+				WHERE ts.task_id = t.id AND m.user_id = $1
+			)
+		  )
+		  AND (t.starts_at IS NULL OR t.starts_at <= now())
+		  AND (t.ends_at IS NULL OR t.ends_at > now())
+		  AND (t.max_completions IS NULL OR t.completions < t.max_completions)
+		  AND (t.max_per_user IS NULL OR (
+			SELECT COUNT(*) FROM user_tasks ut
+			WHERE ut.task_id = t.id AND ut.user_id = $1 AND ut.status <> 'rejected'
+		  ) < t.max_per_user)
+		  AND NOT EXISTS (
+			SELECT 1 FROM task_prerequisites tp
+			WHERE tp.task_id = t.id
+			  AND NOT EXISTS (
+				SELECT 1 FROM user_tasks ut
+				WHERE ut.task_id = tp.prerequisite_id AND ut.user_id = $1 AND ut.status = 'completed'
+			  )
+		  )
 		  AND NOT EXISTS (
 			SELECT 1 FROM user_tasks ut
-			WHERE ut.task_id = t.id AND ut.user_id = $1
+			WHERE ut.task_id = t.id AND ut.user_id = $1 AND ut.status <> 'rejected'
+			  AND ut.period_start = task_period_start(t.recurrence, t.recurrence_hours, now(), $2)
 		  )
-		ORDER BY t.created_at ASC`
+		ORDER BY c.sort_order NULLS LAST, t.sort_order, t.created_at ASC`
+
+	return queryTasks(ctx, r.db, query, audience.UserID, r.timezone, audience.Level, audience.LifetimePoints)
+}
+
+// GetCompletedTaskIDs retrieves IDs of all tasks the user has completed (and got verified) at least once
+func (r *UserTaskRepository) GetCompletedTaskIDs(ctx context.Context, userID int64) ([]int64, error) {
+	query := `
+		SELECT DISTINCT task_id
+		FROM user_tasks
+		WHERE user_id = $1 AND status = 'completed'
+		ORDER BY task_id`
 
 	rows, err := r.db.Query(ctx, query, userID)
 	if err != nil {
@@ -105,22 +449,14 @@ func (r *UserTaskRepository) GetAvailableTasksForUser(ctx context.Context, userI
 	}
 	defer rows.Close()
 
-	var tasks []*entities.Task
+	var taskIDs []int64
 	for rows.Next() {
-		var task entities.Task
-		err := rows.Scan(
-			&task.ID,
-			&task.Code,
-			&task.Title,
-			&task.RewardPoints,
-			&task.IsActive,
-			&task.CreatedAt,
-		)
-		if err != nil {
+		var taskID int64
+		if err := rows.Scan(&taskID); err != nil {
 			return nil, err
 		}
-		tasks = append(tasks, &task)
+		taskIDs = append(taskIDs, taskID)
 	}
 
-	return tasks, rows.Err()
+	return taskIDs, rows.Err()
 }
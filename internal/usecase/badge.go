@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+)
+
+// badgeRule checks whether a user satisfies a badge threshold
+type badgeRule struct {
+	events []entities.BadgeEvent
+	check  func(ctx context.Context, userID, threshold int64) (bool, error)
+}
+
+// BadgeUseCase evaluates badge rules on domain events and awards badges
+type BadgeUseCase struct {
+	badgeRepo    interfaces.BadgeRepository
+	userRepo     interfaces.UserRepository
+	userTaskRepo interfaces.UserTaskRepository
+	balanceRepo  interfaces.BalanceRepository
+	rules        map[string]badgeRule
+}
+
+// NewBadgeUseCase creates a new BadgeUseCase instance
+func NewBadgeUseCase(
+	badgeRepo interfaces.BadgeRepository,
+	userRepo interfaces.UserRepository,
+	userTaskRepo interfaces.UserTaskRepository,
+	balanceRepo interfaces.BalanceRepository,
+) *BadgeUseCase {
+	b := &BadgeUseCase{
+		badgeRepo:    badgeRepo,
+		userRepo:     userRepo,
+		userTaskRepo: userTaskRepo,
+		balanceRepo:  balanceRepo,
+	}
+
+	b.rules = map[string]badgeRule{
+		entities.BadgeRuleTasksCompleted: {
+			events: []entities.BadgeEvent{entities.BadgeEventTaskCompleted},
+			check:  b.checkTasksCompleted,
+		},
+		entities.BadgeRuleReferrals: {
+			events: []entities.BadgeEvent{entities.BadgeEventReferralAdded},
+			check:  b.checkReferrals,
+		},
+		entities.BadgeRuleLeaderboardTop: {
+			events: []entities.BadgeEvent{entities.BadgeEventTaskCompleted, entities.BadgeEventPointsChanged},
+			check:  b.checkLeaderboardTop,
+		},
+	}
+
+	return b
+}
+
+// HandleEvent evaluates badge rules triggered by the event and returns newly awarded badges
+func (b *BadgeUseCase) HandleEvent(ctx context.Context, event entities.BadgeEvent, userID int64) ([]*entities.Badge, error) {
+	badges, err := b.badgeRepo.GetActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var awarded []*entities.Badge
+	for _, badge := range badges {
+		rule, ok := b.rules[badge.RuleType]
+		if !ok || !triggeredBy(rule, event) {
+			continue
+		}
+
+		earned, err := rule.check(ctx, userID, badge.Threshold)
+		if err != nil {
+			return nil, err
+		}
+		if !earned {
+			continue
+		}
+
+		isNew, err := b.badgeRepo.Award(ctx, userID, badge.ID)
+		if err != nil {
+			return nil, err
+		}
+		if isNew {
+			awarded = append(awarded, badge)
+		}
+	}
+
+	return awarded, nil
+}
+
+// GetUserBadges returns badges earned by user
+func (b *BadgeUseCase) GetUserBadges(ctx context.Context, userID int64) ([]*entities.UserBadge, error) {
+	return b.badgeRepo.GetByUserID(ctx, userID)
+}
+
+// checkTasksCompleted checks that user completed at least threshold verified tasks
+func (b *BadgeUseCase) checkTasksCompleted(ctx context.Context, userID, threshold int64) (bool, error) {
+	userTasks, err := b.userTaskRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	var completed int64
+	for _, ut := range userTasks {
+		if ut.Status == entities.UserTaskStatusCompleted {
+			completed++
+		}
+	}
+	return completed >= threshold, nil
+}
+
+// checkReferrals checks that user referred at least threshold users
+func (b *BadgeUseCase) checkReferrals(ctx context.Context, userID, threshold int64) (bool, error) {
+	user, err := b.userRepo.GetWithReferrals(ctx, userID)
+	if err != nil || user == nil {
+		return false, err
+	}
+	return user.ReferralCount >= threshold, nil
+}
+
+// checkLeaderboardTop checks that user is within the top threshold of the leaderboard
+func (b *BadgeUseCase) checkLeaderboardTop(ctx context.Context, userID, threshold int64) (bool, error) {
+	rank, err := b.balanceRepo.GetRank(ctx, userID, entities.DefaultCurrency, entities.RankingCompetition)
+	if err != nil || rank == nil {
+		return false, err
+	}
+	return rank.Rank <= threshold, nil
+}
+
+// triggeredBy reports whether the rule is evaluated for the event
+func triggeredBy(rule badgeRule, event entities.BadgeEvent) bool {
+	for _, e := range rule.events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
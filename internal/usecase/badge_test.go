@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+)
+
+// stubBadgeRepo serves fixed badges and records awards
+type stubBadgeRepo struct {
+	badges  []*entities.Badge
+	awarded map[int64]bool
+}
+
+func (s *stubBadgeRepo) GetActive(ctx context.Context) ([]*entities.Badge, error) {
+	return s.badges, nil
+}
+
+func (s *stubBadgeRepo) Award(ctx context.Context, userID, badgeID int64) (bool, error) {
+	if s.awarded[badgeID] {
+		return false, nil
+	}
+	s.awarded[badgeID] = true
+	return true, nil
+}
+
+func (s *stubBadgeRepo) GetByUserID(ctx context.Context, userID int64) ([]*entities.UserBadge, error) {
+	return nil, nil
+}
+
+// stubBadgeUsers serves the referral count of a user, other methods are not used by badges
+type stubBadgeUsers struct {
+	interfaces.UserRepository
+	user    *entities.UserWithReferrals
+	lookups int
+}
+
+func (s *stubBadgeUsers) GetWithReferrals(ctx context.Context, id int64) (*entities.UserWithReferrals, error) {
+	s.lookups++
+	return s.user, nil
+}
+
+// stubBadgeUserTasks serves the submissions of a user
+type stubBadgeUserTasks struct {
+	interfaces.UserTaskRepository
+	statuses []string
+	lookups  int
+}
+
+func (s *stubBadgeUserTasks) GetByUserID(ctx context.Context, userID int64) ([]*entities.UserTaskWithDetails, error) {
+	s.lookups++
+	userTasks := make([]*entities.UserTaskWithDetails, 0, len(s.statuses))
+	for i, status := range s.statuses {
+		userTasks = append(userTasks, &entities.UserTaskWithDetails{ID: int64(i + 1), UserID: userID, Status: status})
+	}
+	return userTasks, nil
+}
+
+// stubBadgeBalances serves the leaderboard rank of a user
+type stubBadgeBalances struct {
+	interfaces.BalanceRepository
+	rank    *entities.UserRank
+	lookups int
+}
+
+func (s *stubBadgeBalances) GetRank(ctx context.Context, userID int64, currency string, mode entities.RankingMode) (*entities.UserRank, error) {
+	s.lookups++
+	return s.rank, nil
+}
+
+// completedTasks returns n completed submissions followed by the extra statuses
+func completedTasks(n int, extra ...string) []string {
+	statuses := make([]string, 0, n+len(extra))
+	for i := 0; i < n; i++ {
+		statuses = append(statuses, entities.UserTaskStatusCompleted)
+	}
+	return append(statuses, extra...)
+}
+
+func TestBadgeUseCase_HandleEvent(t *testing.T) {
+	badges := []*entities.Badge{
+		{ID: 1, Code: "five_tasks", RuleType: entities.BadgeRuleTasksCompleted, Threshold: 5, IsActive: true},
+		{ID: 2, Code: "three_referrals", RuleType: entities.BadgeRuleReferrals, Threshold: 3, IsActive: true},
+		{ID: 3, Code: "top_ten", RuleType: entities.BadgeRuleLeaderboardTop, Threshold: 10, IsActive: true},
+		{ID: 4, Code: "unknown_rule", RuleType: "unknown", Threshold: 1, IsActive: true},
+	}
+
+	tests := []struct {
+		name      string
+		event     entities.BadgeEvent
+		statuses  []string
+		referrals *entities.UserWithReferrals
+		rank      *entities.UserRank
+		awarded   map[int64]bool
+		want      []int64
+	}{
+		{
+			name:     "completed 5 tasks",
+			event:    entities.BadgeEventTaskCompleted,
+			statuses: completedTasks(5),
+			rank:     &entities.UserRank{Rank: 50},
+			want:     []int64{1},
+		},
+		{
+			name:     "completed 4 tasks with pending and rejected",
+			event:    entities.BadgeEventTaskCompleted,
+			statuses: completedTasks(4, entities.UserTaskStatusPending, entities.UserTaskStatusRejected),
+			rank:     &entities.UserRank{Rank: 50},
+		},
+		{
+			name:     "completed 5 tasks and top 10",
+			event:    entities.BadgeEventTaskCompleted,
+			statuses: completedTasks(6),
+			rank:     &entities.UserRank{Rank: 10},
+			want:     []int64{1, 3},
+		},
+		{
+			name:     "completed 5 tasks already awarded",
+			event:    entities.BadgeEventTaskCompleted,
+			statuses: completedTasks(5),
+			rank:     &entities.UserRank{Rank: 50},
+			awarded:  map[int64]bool{1: true},
+		},
+		{
+			name:      "referred 3",
+			event:     entities.BadgeEventReferralAdded,
+			statuses:  completedTasks(5),
+			referrals: &entities.UserWithReferrals{ID: 1, ReferralCount: 3},
+			rank:      &entities.UserRank{Rank: 1},
+			want:      []int64{2},
+		},
+		{
+			name:      "referred 2",
+			event:     entities.BadgeEventReferralAdded,
+			referrals: &entities.UserWithReferrals{ID: 1, ReferralCount: 2},
+		},
+		{
+			name:  "referrer not found",
+			event: entities.BadgeEventReferralAdded,
+		},
+		{
+			name:      "top 10 on points change",
+			event:     entities.BadgeEventPointsChanged,
+			statuses:  completedTasks(5),
+			referrals: &entities.UserWithReferrals{ID: 1, ReferralCount: 3},
+			rank:      &entities.UserRank{Rank: 10},
+			want:      []int64{3},
+		},
+		{
+			name:  "rank 11 on points change",
+			event: entities.BadgeEventPointsChanged,
+			rank:  &entities.UserRank{Rank: 11},
+		},
+		{
+			name:  "not on the leaderboard",
+			event: entities.BadgeEventPointsChanged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			awarded := tt.awarded
+			if awarded == nil {
+				awarded = make(map[int64]bool)
+			}
+			users := &stubBadgeUsers{user: tt.referrals}
+			userTasks := &stubBadgeUserTasks{statuses: tt.statuses}
+			balances := &stubBadgeBalances{rank: tt.rank}
+			b := NewBadgeUseCase(&stubBadgeRepo{badges: badges, awarded: awarded}, users, userTasks, balances)
+
+			got, err := b.HandleEvent(context.Background(), tt.event, 1)
+			if err != nil {
+				t.Fatalf("Failed to handle event: %v", err)
+			}
+
+			var ids []int64
+			for _, badge := range got {
+				ids = append(ids, badge.ID)
+			}
+			if len(ids) != len(tt.want) {
+				t.Fatalf("Expected badges %v, got %v", tt.want, ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.want[i] {
+					t.Fatalf("Expected badges %v, got %v", tt.want, ids)
+				}
+			}
+
+			// Rules not triggered by the event must not be checked
+			if tt.event != entities.BadgeEventTaskCompleted && userTasks.lookups != 0 {
+				t.Errorf("Expected no task lookups on %s, got %d", tt.event, userTasks.lookups)
+			}
+			if tt.event != entities.BadgeEventReferralAdded && users.lookups != 0 {
+				t.Errorf("Expected no referral lookups on %s, got %d", tt.event, users.lookups)
+			}
+			if tt.event == entities.BadgeEventReferralAdded && balances.lookups != 0 {
+				t.Errorf("Expected no rank lookups on %s, got %d", tt.event, balances.lookups)
+			}
+		})
+	}
+}
+
+func TestTriggeredBy(t *testing.T) {
+	b := NewBadgeUseCase(nil, nil, nil, nil)
+
+	tests := []struct {
+		rule  string
+		event entities.BadgeEvent
+		want  bool
+	}{
+		{entities.BadgeRuleTasksCompleted, entities.BadgeEventTaskCompleted, true},
+		{entities.BadgeRuleTasksCompleted, entities.BadgeEventReferralAdded, false},
+		{entities.BadgeRuleTasksCompleted, entities.BadgeEventPointsChanged, false},
+		{entities.BadgeRuleReferrals, entities.BadgeEventReferralAdded, true},
+		{entities.BadgeRuleReferrals, entities.BadgeEventTaskCompleted, false},
+		{entities.BadgeRuleReferrals, entities.BadgeEventPointsChanged, false},
+		{entities.BadgeRuleLeaderboardTop, entities.BadgeEventTaskCompleted, true},
+		{entities.BadgeRuleLeaderboardTop, entities.BadgeEventPointsChanged, true},
+		{entities.BadgeRuleLeaderboardTop, entities.BadgeEventReferralAdded, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule+"/"+string(tt.event), func(t *testing.T) {
+			if got := triggeredBy(b.rules[tt.rule], tt.event); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/abdullinmm/user-management-api/internal/domain/entities"
 	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
@@ -11,27 +12,100 @@ import (
 type BalanceUseCase struct {
 	balanceRepo     interfaces.BalanceRepository
 	transactionRepo interfaces.TransactionRepository
+	currencyRepo    interfaces.CurrencyRepository
+	location        *time.Location
 }
 
 // NewBalanceUseCase creates a new BalanceUseCase instance
-func NewBalanceUseCase(balanceRepo interfaces.BalanceRepository, transactionRepo interfaces.TransactionRepository) *BalanceUseCase {
+func NewBalanceUseCase(balanceRepo interfaces.BalanceRepository, transactionRepo interfaces.TransactionRepository, currencyRepo interfaces.CurrencyRepository, location *time.Location) *BalanceUseCase {
 	return &BalanceUseCase{
 		balanceRepo:     balanceRepo,
 		transactionRepo: transactionRepo,
+		currencyRepo:    currencyRepo,
+		location:        location,
 	}
 }
 
-// GetUserBalance returns user's current balance
-func (b *BalanceUseCase) GetUserBalance(ctx context.Context, userID int64) (*entities.Balance, error) {
-	return b.balanceRepo.GetByUserID(ctx, userID)
+// GetUserBalance returns user's current balance in the given currency
+func (b *BalanceUseCase) GetUserBalance(ctx context.Context, userID int64, currency string) (*entities.Balance, error) {
+	code, err := resolveCurrency(ctx, b.currencyRepo, currency)
+	if err != nil {
+		return nil, err
+	}
+	return b.balanceRepo.GetByUserID(ctx, userID, code)
+}
+
+// GetLeaderboard returns top users by points in the given currency
+func (b *BalanceUseCase) GetLeaderboard(ctx context.Context, currency string, mode entities.RankingMode, limit, offset int) ([]*entities.LeaderboardEntry, error) {
+	code, err := resolveCurrency(ctx, b.currencyRepo, currency)
+	if err != nil {
+		return nil, err
+	}
+	return b.balanceRepo.GetLeaderboard(ctx, code, mode, limit, offset)
 }
 
-// GetLeaderboard returns top users by points
-func (b *BalanceUseCase) GetLeaderboard(ctx context.Context, limit, offset int) ([]*entities.UserWithBalance, error) {
-	return b.balanceRepo.GetLeaderboard(ctx, limit, offset)
+// GetWindowLeaderboard returns top users by points earned in the current window,
+// with window boundaries computed in the configured timezone
+func (b *BalanceUseCase) GetWindowLeaderboard(ctx context.Context, currency string, window entities.LeaderboardWindow, mode entities.RankingMode, limit, offset int) ([]*entities.LeaderboardEntry, error) {
+	code, err := resolveCurrency(ctx, b.currencyRepo, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	var since *time.Time
+	if start, ok := window.Start(time.Now(), b.location); ok {
+		// Transactions store server-local wall-clock timestamps
+		local := start.Local()
+		since = &local
+	}
+
+	return b.transactionRepo.GetEarnedLeaderboard(ctx, code, since, mode, limit, offset)
+}
+
+// GetUserRank returns user's rank and percentile in the given currency,
+// with up to neighbours users above and below when neighbours is positive
+func (b *BalanceUseCase) GetUserRank(ctx context.Context, userID int64, currency string, mode entities.RankingMode, neighbours int) (*entities.UserRank, error) {
+	code, err := resolveCurrency(ctx, b.currencyRepo, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	rank, err := b.balanceRepo.GetRank(ctx, userID, code, mode)
+	if err != nil {
+		return nil, err
+	}
+	if rank == nil {
+		return nil, &entities.UserNotFoundError{ID: userID}
+	}
+
+	if neighbours > 0 {
+		rank.Neighbours, err = b.balanceRepo.GetNeighbours(ctx, userID, code, mode, neighbours)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return rank, nil
+}
+
+// GetCurrencies returns all available currencies
+func (b *BalanceUseCase) GetCurrencies(ctx context.Context) ([]*entities.Currency, error) {
+	return b.currencyRepo.GetAll(ctx)
 }
 
 // GetTransactionHistory returns user's transaction history
 func (b *BalanceUseCase) GetTransactionHistory(ctx context.Context, userID int64, limit, offset int) ([]*entities.Transaction, error) {
 	return b.transactionRepo.GetByUserID(ctx, userID, limit, offset)
 }
+
+// lifetimePoints returns points a user has earned in the default currency over all time
+func lifetimePoints(ctx context.Context, balanceRepo interfaces.BalanceRepository, userID int64) (int64, error) {
+	balance, err := balanceRepo.GetByUserID(ctx, userID, entities.DefaultCurrency)
+	if err != nil {
+		return 0, err
+	}
+	if balance == nil {
+		return 0, nil
+	}
+	return balance.LifetimePoints, nil
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+)
+
+// CampaignUseCase handles time-boxed reward multiplier campaigns
+type CampaignUseCase struct {
+	campaignRepo interfaces.CampaignRepository
+}
+
+// NewCampaignUseCase creates a new CampaignUseCase instance
+func NewCampaignUseCase(campaignRepo interfaces.CampaignRepository) *CampaignUseCase {
+	return &CampaignUseCase{
+		campaignRepo: campaignRepo,
+	}
+}
+
+// GetRunning returns campaigns boosting rewards right now
+func (c *CampaignUseCase) GetRunning(ctx context.Context) ([]*entities.Campaign, error) {
+	return c.campaignRepo.GetRunning(ctx)
+}
+
+// GetAll returns all campaigns, latest first
+func (c *CampaignUseCase) GetAll(ctx context.Context) ([]*entities.Campaign, error) {
+	return c.campaignRepo.GetAll(ctx)
+}
+
+// Create validates and stores a new active campaign
+func (c *CampaignUseCase) Create(ctx context.Context, campaign *entities.Campaign) (*entities.Campaign, error) {
+	campaign.Code = strings.TrimSpace(campaign.Code)
+	campaign.Title = strings.TrimSpace(campaign.Title)
+	campaign.Categories = uniqueStrings(campaign.Categories)
+	campaign.IsActive = true
+
+	if err := campaign.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := c.campaignRepo.Create(ctx, campaign); err != nil {
+		return nil, fmt.Errorf("failed to create campaign: %w", err)
+	}
+	return campaign, nil
+}
+
+// Boost returns extra points a running campaign adds to amount points of target (a task
+// reward or a referral bonus) and the ID of that campaign. The campaign with the highest
+// multiplier that still has budget wins, the bonus is charged to its budget. Callers
+// Refund the bonus if they fail to award it
+func (c *CampaignUseCase) Boost(ctx context.Context, target string, task *entities.Task, amount int64) (int64, *int64, error) {
+	if amount <= 0 {
+		return 0, nil, nil
+	}
+
+	campaigns, err := c.campaignRepo.GetRunning(ctx)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to load campaigns: %w", err)
+	}
+
+	now := time.Now()
+	for _, campaign := range campaigns {
+		if !campaign.IsRunning(now) || !campaign.Applies(target, task) {
+			continue
+		}
+
+		bonus := campaign.Bonus(amount)
+		if bonus == 0 {
+			continue
+		}
+
+		// Budget may have run out since campaigns were loaded
+		charged, err := c.campaignRepo.Spend(ctx, campaign.ID, bonus)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to charge campaign budget: %w", err)
+		}
+		if charged > 0 {
+			return charged, &campaign.ID, nil
+		}
+	}
+
+	return 0, nil, nil
+}
+
+// Refund returns a bonus charged by Boost to the campaign budget when it could not be awarded
+func (c *CampaignUseCase) Refund(ctx context.Context, campaignID *int64, bonus int64) {
+	if campaignID == nil || bonus <= 0 {
+		return
+	}
+	if err := c.campaignRepo.Refund(ctx, *campaignID, bonus); err != nil {
+		log.Printf("failed to refund %d points to campaign %d: %v", bonus, *campaignID, err)
+	}
+}
+
+// uniqueStrings returns non-empty trimmed values without duplicates
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		unique = append(unique, value)
+	}
+	return unique
+}
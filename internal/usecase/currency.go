@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+)
+
+// resolveCurrency returns the currency code to use, falling back to the default currency
+func resolveCurrency(ctx context.Context, currencyRepo interfaces.CurrencyRepository, code string) (string, error) {
+	if code == "" {
+		return entities.DefaultCurrency, nil
+	}
+
+	currency, err := currencyRepo.GetByCode(ctx, code)
+	if err != nil {
+		return "", err
+	}
+	if currency == nil {
+		return "", &entities.CurrencyNotFoundError{Code: code}
+	}
+
+	return currency.Code, nil
+}
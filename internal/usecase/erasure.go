@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+)
+
+// erasureBatchSize limits how many users are anonymised per run
+const erasureBatchSize = 100
+
+// ErasureUseCase handles account deletion: users are soft deleted, can be restored
+// during a grace period and are anonymised afterwards
+type ErasureUseCase struct {
+	userRepo    interfaces.UserRepository
+	balanceRepo interfaces.BalanceRepository
+	leaderboard interfaces.LeaderboardRefresher
+	gracePeriod time.Duration
+}
+
+// NewErasureUseCase creates a new ErasureUseCase instance
+func NewErasureUseCase(
+	userRepo interfaces.UserRepository,
+	balanceRepo interfaces.BalanceRepository,
+	leaderboard interfaces.LeaderboardRefresher,
+	gracePeriod time.Duration,
+) *ErasureUseCase {
+	return &ErasureUseCase{
+		userRepo:    userRepo,
+		balanceRepo: balanceRepo,
+		leaderboard: leaderboard,
+		gracePeriod: gracePeriod,
+	}
+}
+
+// DeleteAccount soft deletes a user, who is anonymised once the grace period is over
+func (e *ErasureUseCase) DeleteAccount(ctx context.Context, userID int64) (*entities.AccountDeletion, error) {
+	deletedAt, err := e.userRepo.SoftDelete(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.refreshLeaderboards(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return &entities.AccountDeletion{
+		UserID:     userID,
+		DeletedAt:  deletedAt,
+		EraseAfter: deletedAt.Add(e.gracePeriod),
+	}, nil
+}
+
+// RestoreAccount undeletes a user during the grace period
+func (e *ErasureUseCase) RestoreAccount(ctx context.Context, userID int64) error {
+	restored, err := e.userRepo.Restore(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !restored {
+		return &entities.UserNotFoundError{ID: userID}
+	}
+
+	return e.refreshLeaderboards(ctx, userID)
+}
+
+// Erase anonymises a user right away on behalf of an admin and returns the deletion
+// receipt, erasing an anonymised user again returns the existing receipt
+func (e *ErasureUseCase) Erase(ctx context.Context, userID int64, requestedBy *int64) (*entities.DeletionReceipt, error) {
+	receipt, err := e.userRepo.GetDeletionReceipt(ctx, userID)
+	if err != nil || receipt != nil {
+		return receipt, err
+	}
+
+	receipt, err = e.userRepo.Anonymize(ctx, userID, requestedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.refreshLeaderboards(ctx, userID); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// GetReceipt returns the deletion receipt of an anonymised user
+func (e *ErasureUseCase) GetReceipt(ctx context.Context, userID int64) (*entities.DeletionReceipt, error) {
+	return e.userRepo.GetDeletionReceipt(ctx, userID)
+}
+
+// Run periodically anonymises users whose grace period is over until ctx is done
+func (e *ErasureUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			userIDs, err := e.userRepo.GetDeletedBefore(ctx, time.Now().Add(-e.gracePeriod), erasureBatchSize)
+			if err != nil {
+				log.Printf("failed to fetch deleted users: %v", err)
+				continue
+			}
+
+			for _, userID := range userIDs {
+				if _, err := e.userRepo.Anonymize(ctx, userID, nil); err != nil {
+					log.Printf("failed to anonymize user %d: %v", userID, err)
+				}
+			}
+		}
+	}
+}
+
+// refreshLeaderboards drops deleted users from cached leaderboards or brings restored ones back
+func (e *ErasureUseCase) refreshLeaderboards(ctx context.Context, userID int64) error {
+	balances, err := e.balanceRepo.GetAllByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, balance := range balances {
+		e.leaderboard.Refresh(ctx, userID, balance.Currency)
+	}
+	return nil
+}
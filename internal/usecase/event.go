@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+)
+
+// subscriberBuffer is the number of events buffered per stream before dropping
+const subscriberBuffer = 64
+
+// EventUseCase handles live update subscriptions
+type EventUseCase struct {
+	broker interfaces.EventBroker
+}
+
+// NewEventUseCase creates a new EventUseCase instance
+func NewEventUseCase(broker interfaces.EventBroker) *EventUseCase {
+	return &EventUseCase{
+		broker: broker,
+	}
+}
+
+// Subscribe returns events addressed to the user or broadcast to everyone until ctx is done
+func (e *EventUseCase) Subscribe(ctx context.Context, userID int64) <-chan entities.Event {
+	events, unsubscribe := e.broker.Subscribe(subscriberBuffer)
+	out := make(chan entities.Event, subscriberBuffer)
+
+	go func() {
+		defer close(out)
+		defer unsubscribe()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-events:
+				if event.UserID != 0 && event.UserID != userID {
+					continue
+				}
+				select {
+				case out <- event:
+				default:
+					// Drop events for slow clients instead of blocking others
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// publishTransaction notifies subscribers about a committed transaction: the user
+// receives the transaction and the new balance, everyone receives the new rank
+func publishTransaction(ctx context.Context, broker interfaces.EventBroker, balanceRepo interfaces.BalanceRepository, transaction *entities.Transaction) {
+	now := time.Now()
+	broker.Publish(entities.Event{Type: entities.EventTransaction, UserID: transaction.UserID, Data: transaction, CreatedAt: now})
+
+	balance, err := balanceRepo.GetByUserID(ctx, transaction.UserID, transaction.Currency)
+	if err != nil || balance == nil {
+		log.Printf("failed to load balance for event of user %d: %v", transaction.UserID, err)
+		return
+	}
+	broker.Publish(entities.Event{Type: entities.EventBalance, UserID: transaction.UserID, Data: balance, CreatedAt: now})
+
+	rank, err := balanceRepo.GetRank(ctx, transaction.UserID, transaction.Currency, entities.RankingCompetition)
+	if err != nil || rank == nil {
+		log.Printf("failed to load rank for event of user %d: %v", transaction.UserID, err)
+		return
+	}
+	broker.Publish(entities.Event{Type: entities.EventLeaderboard, Data: rank, CreatedAt: now})
+}
@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+)
+
+const (
+	// exportPollInterval is how often the export queue is checked
+	exportPollInterval = 5 * time.Second
+	// exportStaleAfter is how long an export may be processing before another worker retries it
+	exportStaleAfter = 10 * time.Minute
+	// exportTransactionsPage is the number of transactions loaded per query
+	exportTransactionsPage = 1000
+)
+
+// ExportUseCase builds archives of everything stored about a user in the background
+type ExportUseCase struct {
+	snapshots       interfaces.SnapshotReader
+	exportRepo      interfaces.DataExportRepository
+	userRepo        interfaces.UserRepository
+	balanceRepo     interfaces.BalanceRepository
+	transactionRepo interfaces.TransactionRepository
+	userTaskRepo    interfaces.UserTaskRepository
+	ttl             time.Duration
+}
+
+// NewExportUseCase creates a new ExportUseCase instance
+func NewExportUseCase(
+	snapshots interfaces.SnapshotReader,
+	exportRepo interfaces.DataExportRepository,
+	userRepo interfaces.UserRepository,
+	balanceRepo interfaces.BalanceRepository,
+	transactionRepo interfaces.TransactionRepository,
+	userTaskRepo interfaces.UserTaskRepository,
+	ttl time.Duration,
+) *ExportUseCase {
+	return &ExportUseCase{
+		snapshots:       snapshots,
+		exportRepo:      exportRepo,
+		userRepo:        userRepo,
+		balanceRepo:     balanceRepo,
+		transactionRepo: transactionRepo,
+		userTaskRepo:    userTaskRepo,
+		ttl:             ttl,
+	}
+}
+
+// RequestExport queues an export of the user's data, an export still in the
+// queue is returned instead of queueing another one
+func (e *ExportUseCase) RequestExport(ctx context.Context, userID int64, format string) (*entities.DataExport, error) {
+	if err := entities.ValidateExportFormat(format); err != nil {
+		return nil, err
+	}
+
+	exports, err := e.exportRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, export := range exports {
+		if export.Format == format && (export.Status == entities.DataExportStatusPending || export.Status == entities.DataExportStatusProcessing) {
+			return export, nil
+		}
+	}
+
+	export := &entities.DataExport{UserID: userID, Format: format}
+	if err := e.exportRepo.Create(ctx, export); err != nil {
+		return nil, fmt.Errorf("failed to queue data export: %w", err)
+	}
+	return export, nil
+}
+
+// GetExports returns the user's exports, latest first
+func (e *ExportUseCase) GetExports(ctx context.Context, userID int64) ([]*entities.DataExport, error) {
+	return e.exportRepo.GetByUserID(ctx, userID)
+}
+
+// GetExport returns an export of the user
+func (e *ExportUseCase) GetExport(ctx context.Context, userID, exportID int64) (*entities.DataExport, error) {
+	return e.export(ctx, userID, exportID, false)
+}
+
+// Download returns a ready, unexpired export of the user with its archive
+func (e *ExportUseCase) Download(ctx context.Context, userID, exportID int64) (*entities.DataExport, error) {
+	export, err := e.export(ctx, userID, exportID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if export.IsExpired(time.Now()) {
+		return nil, &entities.DataExportExpiredError{ID: export.ID}
+	}
+	if export.Status != entities.DataExportStatusReady {
+		return nil, &entities.DataExportNotReadyError{ID: export.ID, Status: export.Status}
+	}
+	return export, nil
+}
+
+// export loads an export, other users' exports are reported as not found
+func (e *ExportUseCase) export(ctx context.Context, userID, exportID int64, withContent bool) (*entities.DataExport, error) {
+	export, err := e.exportRepo.GetByID(ctx, exportID, withContent)
+	if err != nil {
+		return nil, err
+	}
+	if export == nil || export.UserID != userID {
+		return nil, &entities.DataExportNotFoundError{ID: exportID}
+	}
+	return export, nil
+}
+
+// Run builds queued exports and drops expired archives until ctx is done
+func (e *ExportUseCase) Run(ctx context.Context) {
+	ticker := time.NewTicker(exportPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := e.exportRepo.ExpireBefore(ctx, time.Now()); err != nil {
+				log.Printf("failed to expire data exports: %v", err)
+			}
+
+			// Drain the queue
+			for ctx.Err() == nil {
+				export, err := e.exportRepo.ClaimNext(ctx, exportStaleAfter)
+				if err != nil {
+					log.Printf("failed to claim data export: %v", err)
+					break
+				}
+				if export == nil {
+					break
+				}
+				e.process(ctx, export)
+			}
+		}
+	}
+}
+
+// process builds the archive of a claimed export and stores it or the failure
+func (e *ExportUseCase) process(ctx context.Context, export *entities.DataExport) {
+	data, err := e.collect(ctx, export.UserID)
+	var content []byte
+	if err == nil {
+		content, err = data.Archive(export.Format)
+	}
+
+	if err != nil {
+		log.Printf("failed to build data export %d: %v", export.ID, err)
+		if err := e.exportRepo.Fail(ctx, export.ID, "failed to build archive"); err != nil {
+			log.Printf("failed to mark data export %d as failed: %v", export.ID, err)
+		}
+		return
+	}
+
+	if err := e.exportRepo.Complete(ctx, export.ID, content, time.Now().Add(e.ttl)); err != nil {
+		log.Printf("failed to store data export %d: %v", export.ID, err)
+	}
+}
+
+// collect gathers everything stored about the user from one snapshot, so the
+// archive doesn't mix data from before and after concurrent changes
+func (e *ExportUseCase) collect(ctx context.Context, userID int64) (*entities.PersonalData, error) {
+	data := &entities.PersonalData{Transactions: []*entities.Transaction{}}
+
+	err := e.snapshots.ReadSnapshot(ctx, func(ctx context.Context) error {
+		user, err := e.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return &entities.UserNotFoundError{ID: userID}
+		}
+		data.ExportedAt = time.Now()
+		data.User = user
+
+		if data.Balances, err = e.balanceRepo.GetAllByUserID(ctx, userID); err != nil {
+			return err
+		}
+
+		// Page by ID, transactions of a user are only ever appended
+		var afterID int64
+		for {
+			page, err := e.transactionRepo.GetByUserIDAfter(ctx, userID, afterID, exportTransactionsPage)
+			if err != nil {
+				return err
+			}
+			data.Transactions = append(data.Transactions, page...)
+			if len(page) < exportTransactionsPage {
+				break
+			}
+			afterID = page[len(page)-1].ID
+		}
+
+		if data.Tasks, err = e.userTaskRepo.GetByUserID(ctx, userID); err != nil {
+			return err
+		}
+		if data.Referrals, err = e.userRepo.GetReferrals(ctx, userID); err != nil {
+			return err
+		}
+		data.UsernameHistory, err = e.userRepo.GetUsernameHistory(ctx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Export empty lists rather than nulls
+	if data.Balances == nil {
+		data.Balances = []*entities.Balance{}
+	}
+	if data.Tasks == nil {
+		data.Tasks = []*entities.UserTaskWithDetails{}
+	}
+	if data.Referrals == nil {
+		data.Referrals = []*entities.Referral{}
+	}
+	if data.UsernameHistory == nil {
+		data.UsernameHistory = []*entities.UsernameChange{}
+	}
+
+	return data, nil
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+)
+
+// ImportUseCase imports users in bulk, e.g. when migrating a partner's user base
+type ImportUseCase struct {
+	userRepo       interfaces.UserRepository
+	currencyRepo   interfaces.CurrencyRepository
+	leaderboard    interfaces.LeaderboardRefresher
+	usernamePolicy *entities.UsernamePolicy
+	batchSize      int
+}
+
+// NewImportUseCase creates a new ImportUseCase instance
+func NewImportUseCase(
+	userRepo interfaces.UserRepository,
+	currencyRepo interfaces.CurrencyRepository,
+	leaderboard interfaces.LeaderboardRefresher,
+	usernamePolicy *entities.UsernamePolicy,
+	batchSize int,
+) *ImportUseCase {
+	return &ImportUseCase{
+		userRepo:       userRepo,
+		currencyRepo:   currencyRepo,
+		leaderboard:    leaderboard,
+		usernamePolicy: usernamePolicy,
+		batchSize:      batchSize,
+	}
+}
+
+// Import reads users from r and creates them in batches with opening balances in
+// the currency. Invalid, duplicate and conflicting rows are listed in the report
+// and don't stop the import, only an unreadable file does
+func (i *ImportUseCase) Import(ctx context.Context, r io.Reader, format, currency string) (*entities.ImportReport, error) {
+	code, err := resolveCurrency(ctx, i.currencyRepo, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := entities.NewImportReader(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entities.ImportReport{Currency: code, Errors: []entities.ImportRowError{}}
+	defer func() {
+		// Batches report their rows after the rows rejected while reading
+		sort.SliceStable(report.Errors, func(a, b int) bool {
+			return report.Errors[a].Line < report.Errors[b].Line
+		})
+		if report.Imported > 0 {
+			i.leaderboard.Invalidate(entities.DefaultCurrency)
+			i.leaderboard.Invalidate(code)
+		}
+	}()
+
+	// Line of the first row of each normalized username in the file
+	seen := make(map[string]int)
+	batch := make([]*entities.ImportRow, 0, i.batchSize)
+
+	for {
+		row, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		var rowErr *entities.InvalidImportRowError
+		if errors.As(err, &rowErr) {
+			report.Total++
+			report.AddError(rowErr.Line, rowErr.Username, rowErr.Reason)
+			continue
+		}
+		if err != nil {
+			return report, err
+		}
+		report.Total++
+
+		if err := i.usernamePolicy.Validate(row.Username); err != nil {
+			report.AddError(row.Line, row.Username, err.Error())
+			continue
+		}
+
+		key := entities.NormalizeUsername(row.Username)
+		if line, ok := seen[key]; ok {
+			report.AddError(row.Line, row.Username, fmt.Sprintf("duplicate of line %d", line))
+			continue
+		}
+		seen[key] = row.Line
+
+		if batch = append(batch, row); len(batch) == i.batchSize {
+			if err := i.importBatch(ctx, batch, report); err != nil {
+				return report, err
+			}
+			batch = batch[:0]
+		}
+	}
+
+	if len(batch) > 0 {
+		if err := i.importBatch(ctx, batch, report); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// importBatch imports a batch and adds its outcome to the report. A failed batch
+// is reported row by row, the import only stops when ctx is done
+func (i *ImportUseCase) importBatch(ctx context.Context, batch []*entities.ImportRow, report *entities.ImportReport) error {
+	imported, rowErrors, err := i.userRepo.Import(ctx, batch, report.Currency)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Printf("failed to import batch of %d users from line %d: %v", len(batch), batch[0].Line, err)
+		for _, row := range batch {
+			report.AddError(row.Line, row.Username, "failed to import batch")
+		}
+		return nil
+	}
+
+	report.Imported += imported
+	for _, rowErr := range rowErrors {
+		report.AddError(rowErr.Line, rowErr.Username, rowErr.Error)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+	"github.com/abdullinmm/user-management-api/internal/pkg/signature"
+)
+
+// PartnerUseCase handles signed task completion callbacks from partners
+type PartnerUseCase struct {
+	partnerRepo interfaces.PartnerRepository
+	taskRepo    interfaces.TaskRepository
+	userRepo    interfaces.UserRepository
+	taskUC      *TaskUseCase
+	maxSkew     time.Duration
+}
+
+// NewPartnerUseCase creates a new PartnerUseCase instance
+func NewPartnerUseCase(
+	partnerRepo interfaces.PartnerRepository,
+	taskRepo interfaces.TaskRepository,
+	userRepo interfaces.UserRepository,
+	taskUC *TaskUseCase,
+	maxSkew time.Duration,
+) *PartnerUseCase {
+	return &PartnerUseCase{
+		partnerRepo: partnerRepo,
+		taskRepo:    taskRepo,
+		userRepo:    userRepo,
+		taskUC:      taskUC,
+		maxSkew:     maxSkew,
+	}
+}
+
+// HandleCallback verifies a callback signed by the partner and completes the task on behalf
+// of the user. Each nonce is accepted once, callbacks older than the allowed skew are rejected
+func (p *PartnerUseCase) HandleCallback(ctx context.Context, partnerCode string, payload []byte, sig string) (*entities.UserTask, error) {
+	partner, err := p.partnerRepo.GetByCode(ctx, partnerCode)
+	if err != nil {
+		return nil, err
+	}
+	if partner == nil || !partner.IsActive {
+		return nil, &entities.PartnerNotFoundError{Code: partnerCode}
+	}
+
+	if !signature.Verify([]byte(partner.Secret), payload, sig) {
+		return nil, &entities.InvalidSignatureError{}
+	}
+
+	var callback entities.PartnerCallback
+	if err := json.Unmarshal(payload, &callback); err != nil {
+		return nil, &entities.InvalidCallbackError{Reason: "malformed JSON payload"}
+	}
+	if err := callback.Validate(time.Now(), p.maxSkew); err != nil {
+		return nil, err
+	}
+
+	// Nonces are kept longer than the timestamp window, so a replayed callback
+	// is rejected either by its nonce or by its timestamp
+	fresh, err := p.partnerRepo.UseNonce(ctx, partner.ID, callback.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		return nil, &entities.CallbackReplayError{Nonce: callback.Nonce}
+	}
+
+	task, err := p.taskRepo.GetByCode(ctx, callback.TaskCode)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, &entities.InvalidCallbackError{Reason: fmt.Sprintf("task %q not found", callback.TaskCode)}
+	}
+	if task.Verification != entities.TaskVerificationCallback || task.PartnerID == nil || *task.PartnerID != partner.ID {
+		return nil, &entities.TaskPartnerMismatchError{TaskCode: task.Code, PartnerCode: partner.Code}
+	}
+
+	user, err := p.userRepo.GetByID(ctx, callback.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, &entities.UserNotFoundError{ID: callback.UserID}
+	}
+
+	return p.taskUC.CompleteVerifiedTask(ctx, user.ID, task, fmt.Sprintf("Verified by partner %s", partner.Code))
+}
+
+// Run periodically purges nonces that can no longer be replayed until ctx is done
+func (p *PartnerUseCase) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.maxSkew)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := p.partnerRepo.PurgeNonces(ctx, time.Now().Add(-2*p.maxSkew)); err != nil {
+				log.Printf("failed to purge partner nonces: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+)
+
+// SegmentUseCase manages explicit user segments targeted by tasks
+type SegmentUseCase struct {
+	segmentRepo interfaces.SegmentRepository
+}
+
+// NewSegmentUseCase creates a new SegmentUseCase instance
+func NewSegmentUseCase(segmentRepo interfaces.SegmentRepository) *SegmentUseCase {
+	return &SegmentUseCase{
+		segmentRepo: segmentRepo,
+	}
+}
+
+// GetSegments returns all segments with their member counts
+func (s *SegmentUseCase) GetSegments(ctx context.Context) ([]*entities.Segment, error) {
+	return s.segmentRepo.GetAll(ctx)
+}
+
+// SaveSegment creates a segment or renames an existing one
+func (s *SegmentUseCase) SaveSegment(ctx context.Context, code, title string) (*entities.Segment, error) {
+	code = strings.TrimSpace(code)
+	title = strings.TrimSpace(title)
+	if code == "" || title == "" {
+		return nil, fmt.Errorf("code and title are required")
+	}
+
+	segment := &entities.Segment{Code: code, Title: title}
+	if err := s.segmentRepo.Save(ctx, segment); err != nil {
+		return nil, fmt.Errorf("failed to save segment: %w", err)
+	}
+	return segment, nil
+}
+
+// AddMembers adds users to a segment and returns the number of users added.
+// Unknown users and current members are skipped
+func (s *SegmentUseCase) AddMembers(ctx context.Context, code string, userIDs []int64) (int64, error) {
+	segment, err := s.getSegment(ctx, code)
+	if err != nil {
+		return 0, err
+	}
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+	return s.segmentRepo.AddMembers(ctx, segment.ID, userIDs)
+}
+
+// RemoveMember removes a user from a segment, SegmentMemberNotFoundError if
+// the user is not a member
+func (s *SegmentUseCase) RemoveMember(ctx context.Context, code string, userID int64) error {
+	segment, err := s.getSegment(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.segmentRepo.RemoveMember(ctx, segment.ID, userID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return &entities.SegmentMemberNotFoundError{Code: segment.Code, UserID: userID}
+	}
+	return nil
+}
+
+// getSegment returns the segment with code or SegmentNotFoundError
+func (s *SegmentUseCase) getSegment(ctx context.Context, code string) (*entities.Segment, error) {
+	segment, err := s.segmentRepo.GetByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if segment == nil {
+		return nil, &entities.SegmentNotFoundError{Code: code}
+	}
+	return segment, nil
+}
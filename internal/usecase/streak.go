@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+)
+
+// StreakUseCase handles streaks of recurring task completions and streak freezes
+type StreakUseCase struct {
+	streakRepo  interfaces.StreakRepository
+	balanceRepo interfaces.BalanceRepository
+	leaderboard interfaces.LeaderboardRefresher
+	broker      interfaces.EventBroker
+	rules       entities.StreakRules
+	freezePrice int64
+}
+
+// NewStreakUseCase creates a new StreakUseCase instance
+func NewStreakUseCase(
+	streakRepo interfaces.StreakRepository,
+	balanceRepo interfaces.BalanceRepository,
+	leaderboard interfaces.LeaderboardRefresher,
+	broker interfaces.EventBroker,
+	rules entities.StreakRules,
+	freezePrice int64,
+) *StreakUseCase {
+	return &StreakUseCase{
+		streakRepo:  streakRepo,
+		balanceRepo: balanceRepo,
+		leaderboard: leaderboard,
+		broker:      broker,
+		rules:       rules,
+		freezePrice: freezePrice,
+	}
+}
+
+// Advance records a completion of a recurring task in the period starting at
+// periodStart, spending streak freezes to bridge missed periods.
+// Returns nil for one-off tasks which have no streaks
+func (s *StreakUseCase) Advance(ctx context.Context, userID int64, task *entities.Task, periodStart time.Time) (*entities.Streak, error) {
+	if !task.IsRecurring() || task.PeriodLength() <= 0 {
+		return nil, nil
+	}
+
+	streak, err := s.streakRepo.Get(ctx, userID, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	if streak == nil {
+		streak = &entities.Streak{UserID: userID, TaskID: task.ID}
+	}
+
+	freezes, err := s.streakRepo.GetFreezes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if used := streak.Advance(periodStart, task.PeriodLength(), freezes); used > 0 {
+		if err := s.streakRepo.AddFreezes(ctx, userID, -used); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.streakRepo.Save(ctx, streak); err != nil {
+		return nil, err
+	}
+
+	return streak, nil
+}
+
+// Multiplier returns the reward multiplier for the streak (1 without a streak)
+func (s *StreakUseCase) Multiplier(streak *entities.Streak) float64 {
+	if streak == nil {
+		return 1
+	}
+	return s.rules.Multiplier(streak.Current)
+}
+
+// Bonus returns the milestone bonus reached by the streak
+func (s *StreakUseCase) Bonus(streak *entities.Streak) int64 {
+	if streak == nil {
+		return 0
+	}
+	return s.rules.Bonus(streak.Current)
+}
+
+// GetSummary returns all streaks of a user and available streak freezes
+func (s *StreakUseCase) GetSummary(ctx context.Context, userID int64) (*entities.StreakSummary, error) {
+	streaks, err := s.streakRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if streaks == nil {
+		streaks = []*entities.Streak{}
+	}
+
+	freezes, err := s.streakRepo.GetFreezes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.StreakSummary{
+		Streaks:          streaks,
+		FreezesAvailable: freezes,
+	}, nil
+}
+
+// PurchaseFreeze spends points on a streak freeze and returns the number of available freezes.
+// The user is charged only if the freeze is added
+func (s *StreakUseCase) PurchaseFreeze(ctx context.Context, userID int64) (int, error) {
+	var payment *entities.Transaction
+	if s.freezePrice > 0 {
+		payment = &entities.Transaction{
+			UserID:        userID,
+			Currency:      entities.DefaultCurrency,
+			Delta:         -s.freezePrice,
+			Reason:        "Streak freeze purchase",
+			ReferenceType: stringPtr("streak_freeze"),
+			CreatedAt:     time.Now(),
+		}
+	}
+
+	// Fails with InsufficientBalanceError if the user can't afford it
+	available, err := s.streakRepo.PurchaseFreeze(ctx, userID, payment)
+	if err != nil {
+		var balanceErr *entities.InsufficientBalanceError
+		if errors.As(err, &balanceErr) {
+			return 0, err
+		}
+		return 0, fmt.Errorf("failed to add streak freeze: %w", err)
+	}
+
+	if payment != nil {
+		s.leaderboard.Refresh(ctx, userID, payment.Currency)
+		publishTransaction(ctx, s.broker, s.balanceRepo, payment)
+	}
+
+	return available, nil
+}
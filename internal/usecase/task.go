@@ -2,7 +2,10 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"math"
 	"time"
 
 	"github.com/abdullinmm/user-management-api/internal/domain/entities"
@@ -13,88 +16,522 @@ import (
 type TaskUseCase struct {
 	taskRepo        interfaces.TaskRepository
 	userTaskRepo    interfaces.UserTaskRepository
+	questRepo       interfaces.QuestChainRepository
+	segmentRepo     interfaces.SegmentRepository
 	balanceRepo     interfaces.BalanceRepository
 	transactionRepo interfaces.TransactionRepository
+	levelRepo       interfaces.LevelRepository
+	badgeUC         *BadgeUseCase
+	streakUC        *StreakUseCase
+	campaignUC      *CampaignUseCase
+	broker          interfaces.EventBroker
+	levels          entities.Levels
+	defaultLocale   string
 }
 
 // NewTaskUseCase creates a new TaskUseCase instance
 func NewTaskUseCase(
 	taskRepo interfaces.TaskRepository,
 	userTaskRepo interfaces.UserTaskRepository,
+	questRepo interfaces.QuestChainRepository,
+	segmentRepo interfaces.SegmentRepository,
 	balanceRepo interfaces.BalanceRepository,
 	transactionRepo interfaces.TransactionRepository,
+	levelRepo interfaces.LevelRepository,
+	badgeUC *BadgeUseCase,
+	streakUC *StreakUseCase,
+	campaignUC *CampaignUseCase,
+	broker interfaces.EventBroker,
+	levels entities.Levels,
+	defaultLocale string,
 ) *TaskUseCase {
 	return &TaskUseCase{
 		taskRepo:        taskRepo,
 		userTaskRepo:    userTaskRepo,
+		questRepo:       questRepo,
+		segmentRepo:     segmentRepo,
 		balanceRepo:     balanceRepo,
 		transactionRepo: transactionRepo,
+		levelRepo:       levelRepo,
+		badgeUC:         badgeUC,
+		streakUC:        streakUC,
+		campaignUC:      campaignUC,
+		broker:          broker,
+		levels:          levels,
+		defaultLocale:   defaultLocale,
 	}
 }
 
-// GetAvailableTasks returns all active tasks
-func (t *TaskUseCase) GetAvailableTasks(ctx context.Context) ([]*entities.Task, error) {
-	return t.taskRepo.GetAll(ctx)
+// GetAvailableTasks returns active tasks except expired ones matching the filter,
+// localized into the most preferred of locales
+func (t *TaskUseCase) GetAvailableTasks(ctx context.Context, filter entities.TaskFilter, locales []string) ([]*entities.Task, error) {
+	tasks, err := t.taskRepo.GetActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return t.localizeTasks(ctx, filterTasks(tasks, filter), locales)
+}
+
+// GetAvailableTasksForUser returns active tasks targeted at the user and matching the filter
+// the user can complete now, localized into the most preferred of locales
+func (t *TaskUseCase) GetAvailableTasksForUser(ctx context.Context, userID int64, filter entities.TaskFilter, locales []string) ([]*entities.Task, error) {
+	audience, err := t.audience(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := t.userTaskRepo.GetAvailableTasksForUser(ctx, audience)
+	if err != nil {
+		return nil, err
+	}
+	return t.localizeTasks(ctx, filterTasks(tasks, filter), locales)
+}
+
+// audience loads the user attributes checked by task targeting rules
+func (t *TaskUseCase) audience(ctx context.Context, userID int64) (*entities.Audience, error) {
+	audience, err := t.segmentRepo.GetAudience(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user audience: %w", err)
+	}
+	if audience == nil {
+		return nil, &entities.UserNotFoundError{ID: userID}
+	}
+
+	audience.Level = t.levels.For(audience.LifetimePoints).Number
+	return audience, nil
+}
+
+// filterTasks returns tasks matching the filter
+func filterTasks(tasks []*entities.Task, filter entities.TaskFilter) []*entities.Task {
+	if filter == (entities.TaskFilter{}) {
+		return tasks
+	}
+
+	var matched []*entities.Task
+	for _, task := range tasks {
+		if filter.Matches(task) {
+			matched = append(matched, task)
+		}
+	}
+	return matched
+}
+
+// localizeTasks translates titles and descriptions of tasks into the most preferred of locales
+func (t *TaskUseCase) localizeTasks(ctx context.Context, tasks []*entities.Task, locales []string) ([]*entities.Task, error) {
+	ids := make([]int64, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+
+	translations, err := t.translations(ctx, ids, locales)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, task := range tasks {
+		task.Localize(translations, locales, t.defaultLocale)
+	}
+	return tasks, nil
+}
+
+// translations loads translations of tasks into preferred locales other than the default one
+func (t *TaskUseCase) translations(ctx context.Context, taskIDs []int64, locales []string) ([]*entities.TaskTranslation, error) {
+	var wanted []string
+	for _, locale := range locales {
+		if locale == t.defaultLocale {
+			break
+		}
+		wanted = append(wanted, locale)
+	}
+	if len(wanted) == 0 {
+		return nil, nil
+	}
+
+	translations, err := t.taskRepo.GetTranslations(ctx, taskIDs, wanted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load task translations: %w", err)
+	}
+	return translations, nil
 }
 
-// CompleteTask marks task as completed for user and awards points
-func (t *TaskUseCase) CompleteTask(ctx context.Context, userID, taskID int64) error {
+// CompleteTask records a task completion for user with an optional proof. Tasks that
+// require verification stay pending until approved, other tasks are rewarded immediately
+func (t *TaskUseCase) CompleteTask(ctx context.Context, userID, taskID int64, proofURL, proofText string) (*entities.UserTask, error) {
 	// Check if task exists
 	task, err := t.taskRepo.GetByID(ctx, taskID)
 	if err != nil {
-		return fmt.Errorf("task not found: %w", err)
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+	if task == nil {
+		return nil, &entities.TaskNotFoundError{ID: taskID}
 	}
 
+	if err := task.ValidateProof(proofURL, proofText); err != nil {
+		return nil, err
+	}
+
+	return t.complete(ctx, userID, task, proofURL, proofText, !task.RequiresVerification())
+}
+
+// CompleteVerifiedTask completes a task verified by an external partner on behalf of
+// the user, approving the user's pending submission of the current period if there is one
+func (t *TaskUseCase) CompleteVerifiedTask(ctx context.Context, userID int64, task *entities.Task, note string) (*entities.UserTask, error) {
+	current, err := t.userTaskRepo.GetForCurrentPeriod(ctx, userID, task.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if current != nil && current.Status == entities.UserTaskStatusPending {
+		return t.ReviewSubmission(ctx, current.ID, true, nil, note)
+	}
+
+	return t.complete(ctx, userID, task, "", "", true)
+}
+
+// complete records a completion of an active, available and unlocked task, rewarding it
+// if verified and leaving it pending otherwise
+func (t *TaskUseCase) complete(ctx context.Context, userID int64, task *entities.Task, proofURL, proofText string, verified bool) (*entities.UserTask, error) {
 	if !task.IsActive {
-		return fmt.Errorf("task is not active")
+		return nil, fmt.Errorf("task is not active")
 	}
 
-	// Check if user already completed this task
-	completed, err := t.userTaskRepo.IsCompleted(ctx, userID, taskID)
+	// Check availability window and total completions cap
+	if err := task.CheckAvailable(time.Now()); err != nil {
+		return nil, err
+	}
+
+	// Check that the user isn't suspended or banned and the task is targeted at them
+	audience, err := t.audience(ctx, userID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if err := audience.AccountStatus.CheckAllowed(userID, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := task.CheckAudience(audience, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// Check that prerequisite tasks are completed
+	if len(task.Prerequisites) > 0 {
+		completedIDs, err := t.userTaskRepo.GetCompletedTaskIDs(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if missing := task.MissingPrerequisites(completedIDs); len(missing) > 0 {
+			return nil, &entities.TaskLockedError{TaskID: task.ID, Missing: missing}
+		}
+	}
+
+	// Check if user already completed this task in the current period
+	completed, err := t.userTaskRepo.IsCompleted(ctx, userID, task.ID)
+	if err != nil {
+		return nil, err
 	}
 
 	if completed {
-		return fmt.Errorf("task already completed")
+		return nil, fmt.Errorf("task already completed")
 	}
 
-	// Record task completion
+	// Record task completion, pending if it has to be verified first
 	userTask := &entities.UserTask{
 		UserID:      userID,
-		TaskID:      taskID,
+		TaskID:      task.ID,
 		CompletedAt: time.Now(),
+		Status:      entities.UserTaskStatusCompleted,
+	}
+	if !verified {
+		userTask.Status = entities.UserTaskStatusPending
+	}
+	if proofURL != "" {
+		userTask.ProofURL = &proofURL
+	}
+	if proofText != "" {
+		userTask.ProofText = &proofText
 	}
 
 	if err := t.userTaskRepo.Create(ctx, userTask); err != nil {
+		var completedErr *entities.TaskAlreadyCompletedError
+		if errors.As(err, &completedErr) {
+			return nil, fmt.Errorf("task already completed")
+		}
+		return nil, err
+	}
+
+	if userTask.Status == entities.UserTaskStatusPending {
+		return userTask, nil
+	}
+
+	if err := t.rewardCompletion(ctx, task, userTask); err != nil {
+		return nil, err
+	}
+
+	return userTask, nil
+}
+
+// GetPendingSubmissions returns the moderation queue of submissions awaiting verification
+func (t *TaskUseCase) GetPendingSubmissions(ctx context.Context, limit, offset int) ([]*entities.UserTaskWithDetails, error) {
+	return t.userTaskRepo.GetPending(ctx, limit, offset)
+}
+
+// ReviewSubmission approves or rejects a pending submission, approved submissions are rewarded
+// or go back to the queue if the reward fails. reviewerID is nil when the submission is verified by an external partner
+func (t *TaskUseCase) ReviewSubmission(ctx context.Context, submissionID int64, approve bool, reviewerID *int64, note string) (*entities.UserTask, error) {
+	status := entities.UserTaskStatusRejected
+	if approve {
+		status = entities.UserTaskStatusCompleted
+	}
+
+	var notePtr *string
+	if note != "" {
+		notePtr = &note
+	}
+
+	// Suspended and banned users can't complete tasks, their submissions stay
+	// pending until the restriction ends or a moderator rejects them
+	if approve {
+		submission, err := t.userTaskRepo.GetByID(ctx, submissionID)
+		if err != nil {
+			return nil, err
+		}
+		if submission == nil {
+			return nil, &entities.SubmissionNotFoundError{ID: submissionID}
+		}
+		audience, err := t.audience(ctx, submission.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if err := audience.AccountStatus.CheckAllowed(submission.UserID, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	userTask, err := t.userTaskRepo.Review(ctx, submissionID, status, reviewerID, notePtr)
+	if err != nil {
+		return nil, err
+	}
+
+	if !approve {
+		return userTask, nil
+	}
+
+	// Return a submission that could not be rewarded to the queue, so it can be approved again
+	if err := t.rewardSubmission(ctx, userTask); err != nil {
+		if reopenErr := t.userTaskRepo.Reopen(ctx, userTask.ID); reopenErr != nil {
+			log.Printf("failed to reopen submission %d: %v", userTask.ID, reopenErr)
+		}
+		return nil, err
+	}
+
+	return userTask, nil
+}
+
+// rewardSubmission rewards an approved submission
+func (t *TaskUseCase) rewardSubmission(ctx context.Context, userTask *entities.UserTask) error {
+	task, err := t.taskRepo.GetByID(ctx, userTask.TaskID)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return &entities.TaskNotFoundError{ID: userTask.TaskID}
+	}
+
+	// Pay the reward of the task version the submission was made against
+	if userTask.TaskVersion != task.Version {
+		versions, err := t.taskRepo.GetVersions(ctx, task.ID)
+		if err != nil {
+			return err
+		}
+		for _, version := range versions {
+			if version.Version == userTask.TaskVersion {
+				task.RewardPoints = version.RewardPoints
+			}
+		}
+	}
+
+	return t.rewardCompletion(ctx, task, userTask)
+}
+
+// rewardCompletion awards task rewards and bonuses for a verified completion and
+// records level-ups and badges it unlocked
+func (t *TaskUseCase) rewardCompletion(ctx context.Context, task *entities.Task, userTask *entities.UserTask) error {
+	userID := userTask.UserID
+
+	// Extend the streak of a recurring task, it scales rewards at milestones
+	streak, err := t.streakUC.Advance(ctx, userID, task, userTask.PeriodStart)
+	if err != nil {
 		return err
 	}
+	multiplier := t.streakUC.Multiplier(streak)
 
-	// Award points if task has rewards
-	if task.RewardPoints > 0 {
-		if err := t.balanceRepo.UpdatePoints(ctx, userID, task.RewardPoints); err != nil {
+	// Remember lifetime points to detect level-ups after rewards
+	lifetimeBefore, err := lifetimePoints(ctx, t.balanceRepo, userID)
+	if err != nil {
+		return err
+	}
+
+	// Award points in every currency the task rewards, a running campaign boosts default currency points
+	var awarded int64
+	for _, reward := range task.Rewards() {
+		amount := int64(math.Round(float64(reward.Amount) * multiplier))
+		var bonus int64
+		var campaignID *int64
+		if reward.Currency == entities.DefaultCurrency {
+			bonus, campaignID, err = t.campaignUC.Boost(ctx, entities.CampaignTargetTasks, task, amount)
+			if err != nil {
+				return err
+			}
+			amount += bonus
+			awarded += amount
+		}
+
+		reason := fmt.Sprintf("Task completed: %s", task.Title)
+		if err := t.award(ctx, userID, reward.Currency, amount, reason, &task.ID, "task", &userTask.ID, campaignID); err != nil {
+			t.campaignUC.Refund(ctx, campaignID, bonus)
 			return err
 		}
+	}
 
-		// Create transaction record
-		transaction := &entities.Transaction{
-			UserID:        userID,
-			Delta:         task.RewardPoints,
-			Reason:        fmt.Sprintf("Task completed: %s", task.Title),
-			ReferenceID:   &taskID,
-			ReferenceType: stringPtr("task"),
-			CreatedAt:     time.Now(),
+	// Keep the paid amount on the completion, task rewards may change later
+	if err := t.userTaskRepo.SetRewardPoints(ctx, userTask.ID, awarded); err != nil {
+		return err
+	}
+	userTask.RewardPoints = &awarded
+
+	// Award the one-time bonus for reaching a streak milestone
+	if bonus := t.streakUC.Bonus(streak); bonus > 0 {
+		reason := fmt.Sprintf("Streak of %d: %s", streak.Current, task.Title)
+		if err := t.award(ctx, userID, entities.DefaultCurrency, bonus, reason, &task.ID, "streak", &userTask.ID, nil); err != nil {
+			return err
 		}
+	}
 
-		if err := t.transactionRepo.Create(ctx, transaction); err != nil {
+	// Award the quest chain bonus once all of its tasks are completed
+	if task.ChainID != nil {
+		if err := t.completeChain(ctx, userID, *task.ChainID); err != nil {
 			return err
 		}
 	}
 
+	if err := t.recordLevelUps(ctx, userID, lifetimeBefore); err != nil {
+		return err
+	}
+
+	// Award badges unlocked by this completion
+	_, err = t.badgeUC.HandleEvent(ctx, entities.BadgeEventTaskCompleted, userID)
+	return err
+}
+
+// award gives points to a user and records the transaction, linked to the completion
+// that earned it if userTaskID is set and to the campaign that boosted it if campaignID is set
+func (t *TaskUseCase) award(ctx context.Context, userID int64, currency string, amount int64, reason string, refID *int64, refType string, userTaskID, campaignID *int64) error {
+	if err := t.balanceRepo.UpdatePoints(ctx, userID, currency, amount); err != nil {
+		return err
+	}
+
+	// Create transaction record
+	transaction := &entities.Transaction{
+		UserID:        userID,
+		Currency:      currency,
+		Delta:         amount,
+		Reason:        reason,
+		ReferenceID:   refID,
+		ReferenceType: stringPtr(refType),
+		UserTaskID:    userTaskID,
+		CampaignID:    campaignID,
+		CreatedAt:     time.Now(),
+	}
+
+	if err := t.transactionRepo.Create(ctx, transaction); err != nil {
+		return err
+	}
+
+	publishTransaction(ctx, t.broker, t.balanceRepo, transaction)
 	return nil
 }
 
-// GetUserTasks returns completed tasks for user
-func (t *TaskUseCase) GetUserTasks(ctx context.Context, userID int64) ([]*entities.UserTaskWithDetails, error) {
-	return t.userTaskRepo.GetByUserID(ctx, userID)
+// completeChain records a completed quest chain and awards its bonus once
+func (t *TaskUseCase) completeChain(ctx context.Context, userID, chainID int64) error {
+	completed, err := t.questRepo.Complete(ctx, userID, chainID)
+	if err != nil || !completed {
+		return err
+	}
+
+	chain, err := t.questRepo.GetByID(ctx, chainID)
+	if err != nil || chain == nil || chain.BonusPoints <= 0 {
+		return err
+	}
+
+	reason := fmt.Sprintf("Quest completed: %s", chain.Title)
+	return t.award(ctx, userID, entities.DefaultCurrency, chain.BonusPoints, reason, &chain.ID, "quest_chain", nil, nil)
+}
+
+// GetUserQuests returns active quest chains with the user's progress
+func (t *TaskUseCase) GetUserQuests(ctx context.Context, userID int64) ([]*entities.UserQuestChain, error) {
+	return t.questRepo.GetUserChains(ctx, userID)
+}
+
+// recordLevelUps records an event for every level the user reached since lifetimeBefore
+func (t *TaskUseCase) recordLevelUps(ctx context.Context, userID, lifetimeBefore int64) error {
+	lifetimeAfter, err := lifetimePoints(ctx, t.balanceRepo, userID)
+	if err != nil {
+		return err
+	}
+
+	previous := t.levels.For(lifetimeBefore)
+	for _, level := range t.levels {
+		if level.Number <= previous.Number || level.MinPoints > lifetimeAfter {
+			continue
+		}
+
+		levelUp := &entities.LevelUp{
+			UserID:         userID,
+			Level:          level.Number,
+			Name:           level.Name,
+			LifetimePoints: lifetimeAfter,
+			CreatedAt:      time.Now(),
+		}
+		if err := t.levelRepo.CreateLevelUp(ctx, levelUp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetTaskVersions returns the title and reward history of a task, latest version first
+func (t *TaskUseCase) GetTaskVersions(ctx context.Context, taskID int64) ([]*entities.TaskVersion, error) {
+	task, err := t.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, &entities.TaskNotFoundError{ID: taskID}
+	}
+
+	return t.taskRepo.GetVersions(ctx, taskID)
+}
+
+// GetUserTasks returns task completion history for user, one entry per period, with
+// task titles localized into the most preferred of locales
+func (t *TaskUseCase) GetUserTasks(ctx context.Context, userID int64, locales []string) ([]*entities.UserTaskWithDetails, error) {
+	userTasks, err := t.userTaskRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(userTasks))
+	for _, userTask := range userTasks {
+		ids = append(ids, userTask.TaskID)
+	}
+
+	translations, err := t.translations(ctx, ids, locales)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, userTask := range userTasks {
+		userTask.Localize(translations, locales, t.defaultLocale)
+	}
+	return userTasks, nil
 }
@@ -2,7 +2,9 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/abdullinmm/user-management-api/internal/domain/entities"
@@ -14,27 +16,63 @@ type UserUseCase struct {
 	userRepo        interfaces.UserRepository
 	balanceRepo     interfaces.BalanceRepository
 	transactionRepo interfaces.TransactionRepository
+	currencyRepo    interfaces.CurrencyRepository
+	badgeUC         *BadgeUseCase
+	campaignUC      *CampaignUseCase
+	broker          interfaces.EventBroker
+	leaderboard     interfaces.LeaderboardRefresher
 	referralBonus   int64
 	refereeBonus    int64
+	levels          entities.Levels
+	usernamePolicy  *entities.UsernamePolicy
 }
 
 // NewUserUseCase creates a new UserUseCase instance
-func NewUserUseCase(userRepo interfaces.UserRepository, balanceRepo interfaces.BalanceRepository, transactionRepo interfaces.TransactionRepository, referralBonus int64, refereeBonus int64) *UserUseCase {
+func NewUserUseCase(
+	userRepo interfaces.UserRepository,
+	balanceRepo interfaces.BalanceRepository,
+	transactionRepo interfaces.TransactionRepository,
+	currencyRepo interfaces.CurrencyRepository,
+	badgeUC *BadgeUseCase,
+	campaignUC *CampaignUseCase,
+	broker interfaces.EventBroker,
+	leaderboard interfaces.LeaderboardRefresher,
+	referralBonus int64,
+	refereeBonus int64,
+	levels entities.Levels,
+	usernamePolicy *entities.UsernamePolicy,
+) *UserUseCase {
 	return &UserUseCase{
 		userRepo:        userRepo,
 		balanceRepo:     balanceRepo,
 		transactionRepo: transactionRepo,
+		currencyRepo:    currencyRepo,
+		badgeUC:         badgeUC,
+		campaignUC:      campaignUC,
+		broker:          broker,
+		leaderboard:     leaderboard,
 		referralBonus:   referralBonus,
 		refereeBonus:    refereeBonus,
+		levels:          levels,
+		usernamePolicy:  usernamePolicy,
 	}
 }
 
 // CreateUser creates a new user and handles referral bonuses
 func (u *UserUseCase) CreateUser(ctx context.Context, username string, referrerID *int64) (*entities.User, error) {
+	username = strings.TrimSpace(username)
+	if err := u.usernamePolicy.Validate(username); err != nil {
+		return nil, err
+	}
+
 	user := &entities.User{
-		Username:   username,
-		CreatedAt:  time.Now(),
-		ReferrerID: referrerID,
+		Username:           username,
+		NormalizedUsername: entities.NormalizeUsername(username),
+		CreatedAt:          time.Now(),
+		ReferrerID:         referrerID,
+	}
+	if err := u.checkUsernameAvailable(ctx, user); err != nil {
+		return nil, err
 	}
 
 	// Create user record
@@ -43,7 +81,7 @@ func (u *UserUseCase) CreateUser(ctx context.Context, username string, referrerI
 	}
 
 	// Initialize user balance with 0 points
-	if err := u.balanceRepo.UpdatePoints(ctx, user.ID, 0); err != nil {
+	if err := u.balanceRepo.UpdatePoints(ctx, user.ID, entities.DefaultCurrency, 0); err != nil {
 		return nil, err
 	}
 
@@ -51,16 +89,18 @@ func (u *UserUseCase) CreateUser(ctx context.Context, username string, referrerI
 	if referrerID != nil {
 		// Give bonus to referee (new user)
 		if u.refereeBonus > 0 {
-			if err := u.givePoints(ctx, user.ID, u.refereeBonus, "Referral signup bonus", nil, nil); err != nil {
+			if err := u.giveReferralBonus(ctx, user.ID, u.refereeBonus, "Referral signup bonus", nil, nil); err != nil {
 				return nil, err
 			}
 		}
 
 		// Give bonus to referrer
-		if u.referralBonus > 0 {
-			if err := u.givePoints(ctx, *referrerID, u.referralBonus, "Referral reward", &user.ID, stringPtr("referral")); err != nil {
-				return nil, err
-			}
+		if err := u.rewardReferrer(ctx, *referrerID, user.ID); err != nil {
+			return nil, err
+		}
+
+		if err := u.awardReferralBadges(ctx, user.ID, *referrerID); err != nil {
+			return nil, err
 		}
 	}
 
@@ -77,9 +117,13 @@ func (u *UserUseCase) GetUserWithReferrals(ctx context.Context, userID int64) (*
 	return u.userRepo.GetWithReferrals(ctx, userID)
 }
 
-// GetUserByUsername returns user by username
+// GetUserByUsername returns user by username, falling back to case variants and look-alikes
 func (u *UserUseCase) GetUserByUsername(ctx context.Context, username string) (*entities.User, error) {
-	return u.userRepo.GetByUsername(ctx, username)
+	user, err := u.userRepo.GetByUsername(ctx, username)
+	if err != nil || user != nil {
+		return user, err
+	}
+	return u.userRepo.GetByNormalizedUsername(ctx, entities.NormalizeUsername(username))
 }
 
 // SetReferrer sets a referrer for existing user (if they don't have one)
@@ -90,6 +134,11 @@ func (u *UserUseCase) SetReferrer(ctx context.Context, userID, referrerID int64)
 		return err
 	}
 
+	// Suspended and banned users don't receive referral rewards
+	if err := user.CheckAllowed(userID, time.Now()); err != nil {
+		return err
+	}
+
 	// Don't allow setting referrer if user already has one
 	if user.ReferrerID != nil {
 		return fmt.Errorf("user already has a referrer")
@@ -102,38 +151,85 @@ func (u *UserUseCase) SetReferrer(ctx context.Context, userID, referrerID int64)
 
 	// Give referral bonuses
 	if u.refereeBonus > 0 {
-		if err := u.givePoints(ctx, userID, u.refereeBonus, "Referral signup bonus", nil, nil); err != nil {
+		if err := u.giveReferralBonus(ctx, userID, u.refereeBonus, "Referral signup bonus", nil, nil); err != nil {
 			return err
 		}
 	}
 
-	if u.referralBonus > 0 {
-		if err := u.givePoints(ctx, referrerID, u.referralBonus, "Referral reward", &userID, stringPtr("referral")); err != nil {
-			return err
-		}
+	if err := u.rewardReferrer(ctx, referrerID, userID); err != nil {
+		return err
 	}
 
+	return u.awardReferralBadges(ctx, userID, referrerID)
+}
+
+// awardReferralBadges evaluates badges unlocked by a new referral and its bonuses
+func (u *UserUseCase) awardReferralBadges(ctx context.Context, userID, referrerID int64) error {
+	if _, err := u.badgeUC.HandleEvent(ctx, entities.BadgeEventReferralAdded, referrerID); err != nil {
+		return err
+	}
+	if _, err := u.badgeUC.HandleEvent(ctx, entities.BadgeEventPointsChanged, referrerID); err != nil {
+		return err
+	}
+	_, err := u.badgeUC.HandleEvent(ctx, entities.BadgeEventPointsChanged, userID)
+	return err
+}
+
+// rewardReferrer gives the referral reward for userID unless the referrer is suspended or banned
+func (u *UserUseCase) rewardReferrer(ctx context.Context, referrerID, userID int64) error {
+	if u.referralBonus <= 0 {
+		return nil
+	}
+
+	referrer, err := u.userRepo.GetByID(ctx, referrerID)
+	if err != nil {
+		return err
+	}
+	if referrer == nil || referrer.IsRestricted(time.Now()) {
+		return nil
+	}
+
+	return u.giveReferralBonus(ctx, referrerID, u.referralBonus, "Referral reward", &userID, stringPtr("referral"))
+}
+
+// giveReferralBonus gives a referral bonus boosted by a running referral campaign
+func (u *UserUseCase) giveReferralBonus(ctx context.Context, userID, points int64, reason string, refID *int64, refType *string) error {
+	bonus, campaignID, err := u.campaignUC.Boost(ctx, entities.CampaignTargetReferrals, nil, points)
+	if err != nil {
+		return err
+	}
+	if err := u.givePoints(ctx, userID, points+bonus, reason, refID, refType, campaignID); err != nil {
+		u.campaignUC.Refund(ctx, campaignID, bonus)
+		return err
+	}
 	return nil
 }
 
-// Helper function to give points and create transaction
-func (u *UserUseCase) givePoints(ctx context.Context, userID, points int64, reason string, refID *int64, refType *string) error {
+// Helper function to give points in the default currency and create transaction
+func (u *UserUseCase) givePoints(ctx context.Context, userID, points int64, reason string, refID *int64, refType *string, campaignID *int64) error {
 	// Update balance
-	if err := u.balanceRepo.UpdatePoints(ctx, userID, points); err != nil {
+	if err := u.balanceRepo.UpdatePoints(ctx, userID, entities.DefaultCurrency, points); err != nil {
 		return err
 	}
 
 	// Create transaction record
 	transaction := &entities.Transaction{
 		UserID:        userID,
+		Currency:      entities.DefaultCurrency,
 		Delta:         points,
 		Reason:        reason,
 		ReferenceID:   refID,
 		ReferenceType: refType,
+		CampaignID:    campaignID,
 		CreatedAt:     time.Now(),
 	}
 
-	return u.transactionRepo.Create(ctx, transaction)
+	if err := u.transactionRepo.Create(ctx, transaction); err != nil {
+		return err
+	}
+
+	publishTransaction(ctx, u.broker, u.balanceRepo, transaction)
+	return nil
 }
 
 // Create is an alias for CreateUser with simpler signature
@@ -141,26 +237,208 @@ func (u *UserUseCase) Create(ctx context.Context, username string) (*entities.Us
 	return u.CreateUser(ctx, username, nil)
 }
 
-// GetByID retrieves a user by ID with balance
-func (u *UserUseCase) GetByID(ctx context.Context, userID int64) (*entities.User, error) {
+// GetByID retrieves a user by ID with balance in the given currency and all balances
+func (u *UserUseCase) GetByID(ctx context.Context, userID int64, currency string) (*entities.User, error) {
+	code, err := resolveCurrency(ctx, u.currencyRepo, currency)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get user
 	user, err := u.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("user not found: %w", err)
 	}
+	if user == nil {
+		return nil, &entities.UserNotFoundError{ID: userID}
+	}
 
 	// Get balance
-	balance, err := u.balanceRepo.GetByUserID(ctx, userID)
-	if err != nil {
+	balance, err := u.balanceRepo.GetByUserID(ctx, userID, code)
+	if err != nil || balance == nil {
 		// If balance doesn't exist, set to 0
 		user.Balance = 0
 	} else {
 		user.Balance = balance.Points
 	}
 
+	// Get balances in all currencies
+	user.Balances, err = u.balanceRepo.GetAllByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
+// UpdateProfile applies a JSON merge patch to the user's username and profile,
+// a new username must satisfy the username policy
+func (u *UserUseCase) UpdateProfile(ctx context.Context, userID int64, patch entities.ProfilePatch) (*entities.User, error) {
+	// Report a taken username before locking the user, the unique constraint still guards races
+	if raw, ok := patch["username"]; ok {
+		var username string
+		if err := json.Unmarshal(raw, &username); err == nil {
+			user := &entities.User{ID: userID, Username: strings.TrimSpace(username)}
+			user.NormalizedUsername = entities.NormalizeUsername(user.Username)
+			if err := u.checkUsernameAvailable(ctx, user); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return u.userRepo.UpdateProfile(ctx, userID, func(user *entities.User) error {
+		previous := user.Username
+		if err := patch.Apply(user); err != nil {
+			return err
+		}
+		if user.Username != previous {
+			if err := u.usernamePolicy.Validate(user.Username); err != nil {
+				return err
+			}
+			user.NormalizedUsername = entities.NormalizeUsername(user.Username)
+		}
+		return nil
+	})
+}
+
+// checkUsernameAvailable returns ProfileFieldTakenError if another user has the same normalized username
+func (u *UserUseCase) checkUsernameAvailable(ctx context.Context, user *entities.User) error {
+	existing, err := u.userRepo.GetByNormalizedUsername(ctx, user.NormalizedUsername)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != user.ID {
+		return &entities.ProfileFieldTakenError{Field: "username", Value: user.Username}
+	}
+	return nil
+}
+
+// GetUsernameHistory returns previous usernames of the user, latest first
+func (u *UserUseCase) GetUsernameHistory(ctx context.Context, userID int64) ([]*entities.UsernameChange, error) {
+	return u.userRepo.GetUsernameHistory(ctx, userID)
+}
+
+// SetStatus suspends, bans or reactivates a user on behalf of a moderator. Only admins
+// can restrict moderators and admins. Restricted users leave leaderboards at once
+func (u *UserUseCase) SetStatus(ctx context.Context, change *entities.UserStatusChange) (*entities.UserStatusChange, error) {
+	if err := change.Validate(time.Now()); err != nil {
+		return nil, err
+	}
+
+	user, err := u.userRepo.GetByID(ctx, change.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, &entities.UserNotFoundError{ID: change.UserID}
+	}
+
+	if user.Role != entities.RoleUser && change.ActorID != nil {
+		actorRole, err := u.GetRole(ctx, *change.ActorID)
+		if err != nil {
+			return nil, err
+		}
+		if actorRole != entities.RoleAdmin {
+			return nil, &entities.InvalidStatusChangeError{Reason: "only admins can change the status of moderators and admins"}
+		}
+	}
+
+	if err := u.userRepo.SetStatus(ctx, change); err != nil {
+		return nil, err
+	}
+
+	// Drop restricted users from cached leaderboards, or bring reactivated ones back
+	balances, err := u.balanceRepo.GetAllByUserID(ctx, change.UserID)
+	if err != nil {
+		return nil, err
+	}
+	for _, balance := range balances {
+		u.leaderboard.Refresh(ctx, change.UserID, balance.Currency)
+	}
+
+	return change, nil
+}
+
+// GetStatusChanges returns the status change audit of the user, latest first
+func (u *UserUseCase) GetStatusChanges(ctx context.Context, userID int64) ([]*entities.UserStatusChange, error) {
+	return u.userRepo.GetStatusChanges(ctx, userID)
+}
+
+// SearchUsers returns a page of users matching the search, continue with
+// the next cursor of the page until it is empty
+func (u *UserUseCase) SearchUsers(ctx context.Context, search *entities.UserSearch) (*entities.UserSearchPage, error) {
+	if err := search.Validate(); err != nil {
+		return nil, err
+	}
+
+	currency, err := resolveCurrency(ctx, u.currencyRepo, search.Currency)
+	if err != nil {
+		return nil, err
+	}
+	search.Currency = currency
+
+	// Users store server-local wall-clock timestamps
+	if search.CreatedFrom != nil {
+		from := search.CreatedFrom.Local()
+		search.CreatedFrom = &from
+	}
+	if search.CreatedTo != nil {
+		to := search.CreatedTo.Local()
+		search.CreatedTo = &to
+	}
+
+	users, err := u.userRepo.Search(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &entities.UserSearchPage{Users: users, Currency: currency}
+	if len(users) > search.Limit {
+		page.Users = users[:search.Limit]
+		page.NextCursor = search.CursorAfter(page.Users[search.Limit-1]).Encode()
+	}
+	if page.Users == nil {
+		page.Users = []*entities.UserSearchResult{}
+	}
+	return page, nil
+}
+
+// CheckAccess returns why the user can't use the API, empty if the user isn't
+// restricted. Tokens of users that no longer exist are denied
+func (u *UserUseCase) CheckAccess(ctx context.Context, userID int64) (string, error) {
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if user == nil {
+		return (&entities.UserNotFoundError{ID: userID}).Error(), nil
+	}
+	if err := user.CheckAllowed(userID, time.Now()); err != nil {
+		return err.Error(), nil
+	}
+	return "", nil
+}
+
+// GetRole returns the user's role, empty if the user doesn't exist
+func (u *UserUseCase) GetRole(ctx context.Context, userID int64) (string, error) {
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return "", err
+	}
+	return user.Role, nil
+}
+
+// GetLevel returns the user's level derived from lifetime earned points
+func (u *UserUseCase) GetLevel(ctx context.Context, userID int64) (*entities.LevelProgress, error) {
+	lifetime, err := lifetimePoints(ctx, u.balanceRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := u.levels.Progress(lifetime)
+	return &progress, nil
+}
+
 // Helper function to create string pointer
 func stringPtr(s string) *string {
 	return &s
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abdullinmm/user-management-api/internal/domain/entities"
+	"github.com/abdullinmm/user-management-api/internal/domain/interfaces"
+)
+
+// stubAccessUsers serves users by ID, other methods are not used by access checks
+type stubAccessUsers struct {
+	interfaces.UserRepository
+	users map[int64]*entities.User
+	err   error
+}
+
+func (s *stubAccessUsers) GetByID(ctx context.Context, id int64) (*entities.User, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.users[id], nil // nil, nil for missing users like the repository
+}
+
+func TestUserUseCase_CheckAccess(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	users := map[int64]*entities.User{
+		1: {ID: 1, AccountStatus: entities.AccountStatus{Status: entities.UserStatusActive}},
+		2: {ID: 2, AccountStatus: entities.AccountStatus{Status: entities.UserStatusBanned}},
+		3: {ID: 3, AccountStatus: entities.AccountStatus{Status: entities.UserStatusSuspended, StatusUntil: &future}},
+		4: {ID: 4, AccountStatus: entities.AccountStatus{Status: entities.UserStatusSuspended, StatusUntil: &past}},
+		5: {ID: 5, AccountStatus: entities.AccountStatus{Status: entities.UserStatusActive, DeletedAt: &past}},
+	}
+
+	tests := []struct {
+		name   string
+		userID int64
+		denied bool
+	}{
+		{"active user", 1, false},
+		{"banned user", 2, true},
+		{"suspended user", 3, true},
+		{"expired suspension", 4, false},
+		{"deleted user", 5, true},
+		{"missing user", 6, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewUserUseCase(&stubAccessUsers{users: users}, nil, nil, nil, nil, nil, nil, nil, 0, 0, nil, nil)
+
+			reason, err := u.CheckAccess(context.Background(), tt.userID)
+			if err != nil {
+				t.Fatalf("Failed to check access: %v", err)
+			}
+			if denied := reason != ""; denied != tt.denied {
+				t.Errorf("Expected denied %v, got reason %q", tt.denied, reason)
+			}
+		})
+	}
+}
+
+func TestUserUseCase_CheckAccessError(t *testing.T) {
+	repoErr := errors.New("connection reset")
+	u := NewUserUseCase(&stubAccessUsers{err: repoErr}, nil, nil, nil, nil, nil, nil, nil, 0, 0, nil, nil)
+
+	// Lookup failures are errors, not denials the caller could cache
+	reason, err := u.CheckAccess(context.Background(), 1)
+	if !errors.Is(err, repoErr) || reason != "" {
+		t.Errorf("Expected lookup error, got reason %q and error %v", reason, err)
+	}
+}
@@ -0,0 +1,25 @@
+-- Drop task rewards
+DROP TABLE IF EXISTS task_rewards;
+
+-- Drop transaction currency
+DROP INDEX IF EXISTS idx_transactions_user_currency;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS fk_transaction_currency;
+ALTER TABLE transactions DROP COLUMN IF EXISTS currency;
+
+-- Restore single-currency balances
+DROP INDEX IF EXISTS idx_balances_currency_points;
+ALTER TABLE balances DROP CONSTRAINT IF EXISTS fk_balance_currency;
+ALTER TABLE balances DROP CONSTRAINT IF EXISTS balances_pkey;
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'balances' AND column_name = 'currency') THEN
+        DELETE FROM balances WHERE currency <> 'points';
+    END IF;
+END $$;
+ALTER TABLE balances DROP COLUMN IF EXISTS currency;
+ALTER TABLE balances ADD PRIMARY KEY (user_id);
+CREATE INDEX IF NOT EXISTS idx_balances_points ON balances(points DESC);
+
+-- Drop currencies
+DROP TABLE IF EXISTS currencies;
@@ -0,0 +1,46 @@
+-- Currencies table
+CREATE TABLE IF NOT EXISTS currencies (
+    code VARCHAR(50) PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    is_spendable BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Default currency plus non-spendable experience points
+INSERT INTO currencies (code, title, is_spendable) VALUES
+    ('points', 'Points', true),
+    ('xp', 'Experience', false)
+ON CONFLICT (code) DO NOTHING;
+
+-- Balances are kept per user and currency
+ALTER TABLE balances ADD COLUMN IF NOT EXISTS currency VARCHAR(50) NOT NULL DEFAULT 'points';
+ALTER TABLE balances DROP CONSTRAINT IF EXISTS balances_pkey;
+ALTER TABLE balances ADD PRIMARY KEY (user_id, currency);
+ALTER TABLE balances DROP CONSTRAINT IF EXISTS fk_balance_currency;
+ALTER TABLE balances ADD CONSTRAINT fk_balance_currency FOREIGN KEY (currency) REFERENCES currencies(code);
+
+-- Leaderboard queries are always scoped to one currency
+DROP INDEX IF EXISTS idx_balances_points;
+CREATE INDEX IF NOT EXISTS idx_balances_currency_points ON balances(currency, points DESC);
+
+-- Every transaction moves points of a single currency
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS currency VARCHAR(50) NOT NULL DEFAULT 'points';
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS fk_transaction_currency;
+ALTER TABLE transactions ADD CONSTRAINT fk_transaction_currency FOREIGN KEY (currency) REFERENCES currencies(code);
+CREATE INDEX IF NOT EXISTS idx_transactions_user_currency ON transactions(user_id, currency);
+
+-- Task rewards in additional currencies (tasks.reward_points stays the default currency reward)
+CREATE TABLE IF NOT EXISTS task_rewards (
+    task_id BIGINT NOT NULL,
+    currency VARCHAR(50) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    PRIMARY KEY (task_id, currency),
+    CONSTRAINT chk_task_reward_currency CHECK (currency <> 'points'),
+    CONSTRAINT fk_task_reward_task FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    CONSTRAINT fk_task_reward_currency FOREIGN KEY (currency) REFERENCES currencies(code)
+);
+
+-- Sample tasks also grant experience
+INSERT INTO task_rewards (task_id, currency, amount)
+SELECT id, 'xp', reward_points FROM tasks
+ON CONFLICT (task_id, currency) DO NOTHING;
@@ -0,0 +1,5 @@
+-- Drop level-up events
+DROP TABLE IF EXISTS level_ups;
+
+-- Drop lifetime points
+ALTER TABLE balances DROP COLUMN IF EXISTS lifetime_points;
@@ -0,0 +1,24 @@
+-- Lifetime earned points per currency (never decreases on spending)
+ALTER TABLE balances ADD COLUMN IF NOT EXISTS lifetime_points BIGINT NOT NULL DEFAULT 0 CHECK (lifetime_points >= 0);
+
+-- Backfill lifetime points from the transaction log
+UPDATE balances b
+SET lifetime_points = COALESCE((
+    SELECT SUM(t.delta)
+    FROM transactions t
+    WHERE t.user_id = b.user_id AND t.currency = b.currency AND t.delta > 0
+), 0);
+
+-- Level-up events
+CREATE TABLE IF NOT EXISTS level_ups (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    level INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    lifetime_points BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_level_up_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Create index for level-up history
+CREATE INDEX IF NOT EXISTS idx_level_ups_user_id ON level_ups(user_id, created_at DESC);
@@ -0,0 +1,3 @@
+-- Drop badges in reverse order (respect foreign keys)
+DROP TABLE IF EXISTS user_badges;
+DROP TABLE IF EXISTS badges;
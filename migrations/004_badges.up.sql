@@ -0,0 +1,31 @@
+-- Badge definitions
+CREATE TABLE IF NOT EXISTS badges (
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(100) NOT NULL UNIQUE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    rule_type VARCHAR(50) NOT NULL,
+    threshold BIGINT NOT NULL CHECK (threshold > 0),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Earned badges
+CREATE TABLE IF NOT EXISTS user_badges (
+    user_id BIGINT NOT NULL,
+    badge_id BIGINT NOT NULL,
+    awarded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, badge_id),
+    CONSTRAINT fk_user_badge_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_badge_badge FOREIGN KEY (badge_id) REFERENCES badges(id) ON DELETE CASCADE
+);
+
+-- Create index for badge listing
+CREATE INDEX IF NOT EXISTS idx_user_badges_awarded_at ON user_badges(user_id, awarded_at DESC);
+
+-- Sample badges
+INSERT INTO badges (code, title, description, rule_type, threshold) VALUES
+    ('BADGE_FIVE_TASKS', 'Task Master', 'Completed 5 tasks', 'tasks_completed', 5),
+    ('BADGE_THREE_REFERRALS', 'Connector', 'Referred 3 friends', 'referrals', 3),
+    ('BADGE_TOP_10', 'Top 10', 'Reached top 10 on the leaderboard', 'leaderboard_top', 10)
+ON CONFLICT (code) DO NOTHING;
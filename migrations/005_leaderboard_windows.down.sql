@@ -0,0 +1,2 @@
+-- Drop windowed leaderboard index
+DROP INDEX IF EXISTS idx_transactions_earned;
@@ -0,0 +1,4 @@
+-- Index for leaderboards over points earned in a time window
+CREATE INDEX IF NOT EXISTS idx_transactions_earned ON transactions(currency, created_at DESC)
+    INCLUDE (user_id, delta)
+    WHERE delta > 0;
@@ -0,0 +1,24 @@
+-- Keep only the first completion of every task before restoring the single-completion key
+DROP INDEX IF EXISTS uq_user_tasks_period;
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'user_tasks' AND column_name = 'id') THEN
+        DELETE FROM user_tasks a
+        USING user_tasks b
+        WHERE a.user_id = b.user_id AND a.task_id = b.task_id AND a.id > b.id;
+    END IF;
+END $$;
+ALTER TABLE user_tasks DROP CONSTRAINT IF EXISTS user_tasks_pkey;
+ALTER TABLE user_tasks DROP COLUMN IF EXISTS period_start;
+ALTER TABLE user_tasks DROP COLUMN IF EXISTS id;
+ALTER TABLE user_tasks ADD PRIMARY KEY (user_id, task_id);
+
+-- Drop period function
+DROP FUNCTION IF EXISTS task_period_start(VARCHAR, INT, TIMESTAMPTZ, TEXT);
+
+-- Drop task recurrence
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS chk_tasks_recurrence_hours;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS chk_tasks_recurrence;
+ALTER TABLE tasks DROP COLUMN IF EXISTS recurrence_hours;
+ALTER TABLE tasks DROP COLUMN IF EXISTS recurrence;
@@ -0,0 +1,49 @@
+-- Task recurrence: once (default), daily, weekly or hourly (every recurrence_hours hours)
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence VARCHAR(20) NOT NULL DEFAULT 'once';
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence_hours INT;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS chk_tasks_recurrence;
+ALTER TABLE tasks ADD CONSTRAINT chk_tasks_recurrence CHECK (recurrence IN ('once', 'daily', 'weekly', 'hourly'));
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS chk_tasks_recurrence_hours;
+ALTER TABLE tasks ADD CONSTRAINT chk_tasks_recurrence_hours CHECK (recurrence <> 'hourly' OR recurrence_hours > 0);
+
+UPDATE tasks SET recurrence = 'daily' WHERE code = 'TASK_DAILY_LOGIN';
+
+-- Start of the recurrence period containing "at", as a UTC timestamp.
+-- Days and weeks (starting Monday) follow the given timezone; one-off tasks have a single period.
+CREATE OR REPLACE FUNCTION task_period_start(recurrence VARCHAR, recurrence_hours INT, at TIMESTAMPTZ, tz TEXT)
+RETURNS TIMESTAMP AS $$
+BEGIN
+    CASE recurrence
+        WHEN 'daily' THEN
+            RETURN (date_trunc('day', at AT TIME ZONE tz) AT TIME ZONE tz) AT TIME ZONE 'UTC';
+        WHEN 'weekly' THEN
+            RETURN (date_trunc('week', at AT TIME ZONE tz) AT TIME ZONE tz) AT TIME ZONE 'UTC';
+        WHEN 'hourly' THEN
+            RETURN to_timestamp(floor(extract(epoch FROM at) / (recurrence_hours * 3600)) * recurrence_hours * 3600) AT TIME ZONE 'UTC';
+        ELSE
+            RETURN TIMESTAMP '1970-01-01 00:00:00';
+    END CASE;
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+-- Completions are recorded once per recurrence period, the (user_id, task_id) key
+-- is replaced only once so re-running keeps the id key
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'user_tasks' AND column_name = 'id'
+    ) THEN
+        ALTER TABLE user_tasks DROP CONSTRAINT IF EXISTS user_tasks_pkey;
+        ALTER TABLE user_tasks ADD COLUMN id BIGSERIAL PRIMARY KEY;
+    END IF;
+END $$;
+ALTER TABLE user_tasks ADD COLUMN IF NOT EXISTS period_start TIMESTAMP NOT NULL DEFAULT TIMESTAMP '1970-01-01 00:00:00';
+
+UPDATE user_tasks ut
+SET period_start = task_period_start(t.recurrence, t.recurrence_hours, ut.completed_at::timestamptz, 'UTC')
+FROM tasks t
+WHERE ut.task_id = t.id;
+
+ALTER TABLE user_tasks ALTER COLUMN period_start DROP DEFAULT;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_user_tasks_period ON user_tasks(user_id, task_id, period_start);
@@ -0,0 +1,3 @@
+-- Drop streak tables
+DROP TABLE IF EXISTS streak_freezes;
+DROP TABLE IF EXISTS user_streaks;
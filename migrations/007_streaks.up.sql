@@ -0,0 +1,20 @@
+-- Streaks of consecutive periods per user and recurring task
+CREATE TABLE IF NOT EXISTS user_streaks (
+    user_id BIGINT NOT NULL,
+    task_id BIGINT NOT NULL,
+    current_streak INT NOT NULL DEFAULT 0 CHECK (current_streak >= 0),
+    longest_streak INT NOT NULL DEFAULT 0 CHECK (longest_streak >= 0),
+    last_period_start TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, task_id),
+    CONSTRAINT fk_user_streak_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_streak_task FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+-- Streak freeze items owned by users
+CREATE TABLE IF NOT EXISTS streak_freezes (
+    user_id BIGINT PRIMARY KEY,
+    available INT NOT NULL DEFAULT 0 CHECK (available >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_streak_freeze_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
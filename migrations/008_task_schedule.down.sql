@@ -0,0 +1,11 @@
+-- Drop completion caps
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS chk_tasks_max_per_user;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS chk_tasks_max_completions;
+ALTER TABLE tasks DROP COLUMN IF EXISTS completions;
+ALTER TABLE tasks DROP COLUMN IF EXISTS max_per_user;
+ALTER TABLE tasks DROP COLUMN IF EXISTS max_completions;
+
+-- Drop availability window
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS chk_tasks_window;
+ALTER TABLE tasks DROP COLUMN IF EXISTS ends_at;
+ALTER TABLE tasks DROP COLUMN IF EXISTS starts_at;
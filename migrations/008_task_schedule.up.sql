@@ -0,0 +1,18 @@
+-- Task availability window (open-ended when NULL)
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS starts_at TIMESTAMPTZ;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS ends_at TIMESTAMPTZ;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS chk_tasks_window;
+ALTER TABLE tasks ADD CONSTRAINT chk_tasks_window CHECK (starts_at IS NULL OR ends_at IS NULL OR ends_at > starts_at);
+
+-- Completion caps: total over all users and per user over all periods (unlimited when NULL)
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS max_completions BIGINT;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS max_per_user INT;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS completions BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS chk_tasks_max_completions;
+ALTER TABLE tasks ADD CONSTRAINT chk_tasks_max_completions CHECK (max_completions IS NULL OR max_completions > 0);
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS chk_tasks_max_per_user;
+ALTER TABLE tasks ADD CONSTRAINT chk_tasks_max_per_user CHECK (max_per_user IS NULL OR max_per_user > 0);
+
+-- Count completions recorded before the counter existed
+UPDATE tasks t
+SET completions = (SELECT COUNT(*) FROM user_tasks ut WHERE ut.task_id = t.id);
@@ -0,0 +1,6 @@
+-- Drop quest chains and prerequisites
+DROP TABLE IF EXISTS user_quest_chains;
+DROP TABLE IF EXISTS task_prerequisites;
+DROP INDEX IF EXISTS idx_tasks_chain_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS chain_id;
+DROP TABLE IF EXISTS quest_chains;
@@ -0,0 +1,48 @@
+-- Quest chains: groups of tasks with a bonus for completing all of them
+CREATE TABLE IF NOT EXISTS quest_chains (
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(100) NOT NULL UNIQUE,
+    title VARCHAR(255) NOT NULL,
+    bonus_points BIGINT NOT NULL DEFAULT 0 CHECK (bonus_points >= 0),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A task belongs to at most one chain
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS chain_id BIGINT REFERENCES quest_chains(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_chain_id ON tasks(chain_id);
+
+-- Tasks that must be completed before a task unlocks
+CREATE TABLE IF NOT EXISTS task_prerequisites (
+    task_id BIGINT NOT NULL,
+    prerequisite_id BIGINT NOT NULL,
+    PRIMARY KEY (task_id, prerequisite_id),
+    CONSTRAINT chk_task_prerequisite_self CHECK (task_id <> prerequisite_id),
+    CONSTRAINT fk_task_prerequisite_task FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    CONSTRAINT fk_task_prerequisite_prerequisite FOREIGN KEY (prerequisite_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+-- Completed chains, the bonus is awarded once per user
+CREATE TABLE IF NOT EXISTS user_quest_chains (
+    user_id BIGINT NOT NULL,
+    chain_id BIGINT NOT NULL,
+    completed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, chain_id),
+    CONSTRAINT fk_user_quest_chain_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_quest_chain_chain FOREIGN KEY (chain_id) REFERENCES quest_chains(id) ON DELETE CASCADE
+);
+
+-- Sample onboarding quest: register, then complete profile, then share
+INSERT INTO quest_chains (code, title, bonus_points) VALUES
+    ('QUEST_ONBOARDING', 'Getting Started', 150)
+ON CONFLICT (code) DO NOTHING;
+
+UPDATE tasks
+SET chain_id = (SELECT id FROM quest_chains WHERE code = 'QUEST_ONBOARDING')
+WHERE code IN ('TASK_REGISTER', 'TASK_PROFILE', 'TASK_SOCIAL_SHARE');
+
+INSERT INTO task_prerequisites (task_id, prerequisite_id)
+SELECT t.id, p.id
+FROM tasks t
+JOIN tasks p ON (t.code, p.code) IN (('TASK_PROFILE', 'TASK_REGISTER'), ('TASK_SOCIAL_SHARE', 'TASK_PROFILE'))
+ON CONFLICT DO NOTHING;
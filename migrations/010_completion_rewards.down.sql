@@ -0,0 +1,3 @@
+-- Drop link between transactions and task completions
+DROP INDEX IF EXISTS idx_transactions_user_task_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS user_task_id;
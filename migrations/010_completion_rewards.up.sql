@@ -0,0 +1,3 @@
+-- Link transactions to the task completion that earned them
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS user_task_id BIGINT REFERENCES user_tasks(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_transactions_user_task_id ON transactions(user_task_id) WHERE user_task_id IS NOT NULL;
@@ -0,0 +1,28 @@
+-- Drop moderation queue index
+DROP INDEX IF EXISTS idx_user_tasks_pending;
+
+-- Drop submissions that were never approved
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'user_tasks' AND column_name = 'status') THEN
+        DELETE FROM user_tasks WHERE status <> 'completed';
+    END IF;
+END $$;
+
+-- Drop submission status, proofs and review results
+ALTER TABLE user_tasks DROP CONSTRAINT IF EXISTS chk_user_tasks_status;
+ALTER TABLE user_tasks DROP COLUMN IF EXISTS review_note;
+ALTER TABLE user_tasks DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE user_tasks DROP COLUMN IF EXISTS reviewed_by;
+ALTER TABLE user_tasks DROP COLUMN IF EXISTS proof_text;
+ALTER TABLE user_tasks DROP COLUMN IF EXISTS proof_url;
+ALTER TABLE user_tasks DROP COLUMN IF EXISTS status;
+
+-- Drop task verification
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS chk_tasks_verification;
+ALTER TABLE tasks DROP COLUMN IF EXISTS verification;
+
+-- Drop user roles
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_users_role;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
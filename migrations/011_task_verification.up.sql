@@ -0,0 +1,24 @@
+-- User roles, moderators review task submissions
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user';
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_users_role;
+ALTER TABLE users ADD CONSTRAINT chk_users_role CHECK (role IN ('user', 'moderator', 'admin'));
+
+-- Task verification: auto (trusted), manual (moderator review) or callback (external partner)
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS verification VARCHAR(20) NOT NULL DEFAULT 'auto';
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS chk_tasks_verification;
+ALTER TABLE tasks ADD CONSTRAINT chk_tasks_verification CHECK (verification IN ('auto', 'manual', 'callback'));
+
+UPDATE tasks SET verification = 'manual' WHERE code = 'TASK_SOCIAL_SHARE';
+
+-- Submissions awaiting verification, proofs and review results
+ALTER TABLE user_tasks ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'completed';
+ALTER TABLE user_tasks DROP CONSTRAINT IF EXISTS chk_user_tasks_status;
+ALTER TABLE user_tasks ADD CONSTRAINT chk_user_tasks_status CHECK (status IN ('pending', 'completed', 'rejected'));
+ALTER TABLE user_tasks ADD COLUMN IF NOT EXISTS proof_url TEXT;
+ALTER TABLE user_tasks ADD COLUMN IF NOT EXISTS proof_text TEXT;
+ALTER TABLE user_tasks ADD COLUMN IF NOT EXISTS reviewed_by BIGINT REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE user_tasks ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP;
+ALTER TABLE user_tasks ADD COLUMN IF NOT EXISTS review_note TEXT;
+
+-- Create index for the moderation queue
+CREATE INDEX IF NOT EXISTS idx_user_tasks_pending ON user_tasks(completed_at) WHERE status = 'pending';
@@ -0,0 +1,9 @@
+-- Drop partner nonces
+DROP TABLE IF EXISTS partner_nonces;
+
+-- Drop task partners
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS chk_tasks_callback_partner;
+ALTER TABLE tasks DROP COLUMN IF EXISTS partner_id;
+
+-- Drop partners
+DROP TABLE IF EXISTS partners;
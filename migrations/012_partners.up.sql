@@ -0,0 +1,26 @@
+-- Partners completing tasks in their systems, callbacks are signed with the partner secret
+CREATE TABLE IF NOT EXISTS partners (
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(100) NOT NULL UNIQUE,
+    title VARCHAR(255) NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Tasks verified by callbacks belong to a partner, which can't be deleted while it has tasks
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS partner_id BIGINT REFERENCES partners(id) ON DELETE RESTRICT;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS chk_tasks_callback_partner;
+ALTER TABLE tasks ADD CONSTRAINT chk_tasks_callback_partner CHECK (verification <> 'callback' OR partner_id IS NOT NULL);
+
+-- Used callback nonces for replay protection, purged once older than the allowed clock skew
+CREATE TABLE IF NOT EXISTS partner_nonces (
+    partner_id BIGINT NOT NULL,
+    nonce VARCHAR(128) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (partner_id, nonce),
+    CONSTRAINT fk_partner_nonce_partner FOREIGN KEY (partner_id) REFERENCES partners(id) ON DELETE CASCADE
+);
+
+-- Create index for purging old nonces
+CREATE INDEX IF NOT EXISTS idx_partner_nonces_created_at ON partner_nonces(created_at);
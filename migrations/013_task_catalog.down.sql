@@ -0,0 +1,12 @@
+-- Drop task translations and tags
+DROP TABLE IF EXISTS task_translations;
+DROP TABLE IF EXISTS task_tags;
+
+-- Drop task presentation
+ALTER TABLE tasks DROP COLUMN IF EXISTS sort_order;
+ALTER TABLE tasks DROP COLUMN IF EXISTS icon_url;
+ALTER TABLE tasks DROP COLUMN IF EXISTS description;
+ALTER TABLE tasks DROP COLUMN IF EXISTS category_id;
+
+-- Drop task categories
+DROP TABLE IF EXISTS task_categories;
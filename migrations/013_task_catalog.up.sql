@@ -0,0 +1,78 @@
+-- Task categories (sections of the task list)
+CREATE TABLE IF NOT EXISTS task_categories (
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(100) NOT NULL UNIQUE,
+    title VARCHAR(255) NOT NULL,
+    sort_order INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Task presentation, base title and description are in the default locale
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS category_id BIGINT REFERENCES task_categories(id) ON DELETE SET NULL;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS icon_url TEXT;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS sort_order INT NOT NULL DEFAULT 0;
+
+-- Task tags
+CREATE TABLE IF NOT EXISTS task_tags (
+    task_id BIGINT NOT NULL,
+    tag VARCHAR(50) NOT NULL,
+    PRIMARY KEY (task_id, tag),
+    CONSTRAINT fk_task_tag_task FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+-- Create index for filtering by tag
+CREATE INDEX IF NOT EXISTS idx_task_tags_tag ON task_tags(tag);
+
+-- Localized task titles and descriptions
+CREATE TABLE IF NOT EXISTS task_translations (
+    task_id BIGINT NOT NULL,
+    locale VARCHAR(20) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (task_id, locale),
+    CONSTRAINT fk_task_translation_task FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+-- Sample categories, tags and translations
+INSERT INTO task_categories (code, title, sort_order) VALUES
+    ('onboarding', 'Getting Started', 10),
+    ('social', 'Social', 20),
+    ('daily', 'Daily', 30)
+ON CONFLICT (code) DO NOTHING;
+
+UPDATE tasks t
+SET category_id = c.id, sort_order = v.sort_order, description = v.description
+FROM (VALUES
+    ('TASK_REGISTER', 'onboarding', 10, 'Create your account'),
+    ('TASK_PROFILE', 'onboarding', 20, 'Fill in your profile details'),
+    ('TASK_FIRST_REFERRAL', 'social', 10, 'Invite a friend to join'),
+    ('TASK_SOCIAL_SHARE', 'social', 20, 'Share a post about us on social media'),
+    ('TASK_DAILY_LOGIN', 'daily', 10, 'Log in every day')
+) AS v(task_code, category_code, sort_order, description)
+JOIN task_categories c ON c.code = v.category_code
+WHERE t.code = v.task_code;
+
+INSERT INTO task_tags (task_id, tag)
+SELECT t.id, v.tag
+FROM (VALUES
+    ('TASK_REGISTER', 'onboarding'),
+    ('TASK_PROFILE', 'onboarding'),
+    ('TASK_FIRST_REFERRAL', 'referral'),
+    ('TASK_SOCIAL_SHARE', 'social'),
+    ('TASK_DAILY_LOGIN', 'daily')
+) AS v(task_code, tag)
+JOIN tasks t ON t.code = v.task_code
+ON CONFLICT DO NOTHING;
+
+INSERT INTO task_translations (task_id, locale, title, description)
+SELECT t.id, 'ru', v.title, v.description
+FROM (VALUES
+    ('TASK_REGISTER', 'Завершите регистрацию', 'Создайте аккаунт'),
+    ('TASK_PROFILE', 'Заполните профиль', 'Укажите данные профиля'),
+    ('TASK_FIRST_REFERRAL', 'Пригласите первого друга', 'Пригласите друга присоединиться'),
+    ('TASK_SOCIAL_SHARE', 'Поделитесь в соцсетях', 'Расскажите о нас в социальных сетях'),
+    ('TASK_DAILY_LOGIN', 'Ежедневный бонус за вход', 'Заходите каждый день')
+) AS v(task_code, title, description)
+JOIN tasks t ON t.code = v.task_code
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,10 @@
+-- Drop segments
+DROP TABLE IF EXISTS task_segments;
+DROP TABLE IF EXISTS user_segment_members;
+DROP TABLE IF EXISTS user_segments;
+
+-- Drop targeting rules
+ALTER TABLE tasks DROP COLUMN IF EXISTS has_referrer;
+ALTER TABLE tasks DROP COLUMN IF EXISTS min_account_age_days;
+ALTER TABLE tasks DROP COLUMN IF EXISTS min_points;
+ALTER TABLE tasks DROP COLUMN IF EXISTS min_level;
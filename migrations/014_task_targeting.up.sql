@@ -0,0 +1,34 @@
+-- Audience targeting rules, NULL rules don't restrict the task
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS min_level INT CHECK (min_level > 0);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS min_points BIGINT CHECK (min_points >= 0);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS min_account_age_days INT CHECK (min_account_age_days >= 0);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS has_referrer BOOLEAN;
+
+-- Explicit user segments
+CREATE TABLE IF NOT EXISTS user_segments (
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(100) NOT NULL UNIQUE,
+    title VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS user_segment_members (
+    segment_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    added_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (segment_id, user_id),
+    CONSTRAINT fk_segment_member_segment FOREIGN KEY (segment_id) REFERENCES user_segments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_segment_member_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Create index for looking up segments of a user
+CREATE INDEX IF NOT EXISTS idx_user_segment_members_user_id ON user_segment_members(user_id);
+
+-- Segments a task is targeted at, a task without segments is targeted at everyone
+CREATE TABLE IF NOT EXISTS task_segments (
+    task_id BIGINT NOT NULL,
+    segment_id BIGINT NOT NULL,
+    PRIMARY KEY (task_id, segment_id),
+    CONSTRAINT fk_task_segment_task FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    CONSTRAINT fk_task_segment_segment FOREIGN KEY (segment_id) REFERENCES user_segments(id) ON DELETE CASCADE
+);
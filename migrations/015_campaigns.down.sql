@@ -0,0 +1,7 @@
+-- Drop campaign link of transactions
+DROP INDEX IF EXISTS idx_transactions_campaign_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS campaign_id;
+
+-- Drop campaigns
+DROP TABLE IF EXISTS campaign_categories;
+DROP TABLE IF EXISTS campaigns;
@@ -0,0 +1,35 @@
+-- Time-boxed campaigns multiplying rewards until their point budget is spent
+CREATE TABLE IF NOT EXISTS campaigns (
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(100) NOT NULL UNIQUE,
+    title VARCHAR(255) NOT NULL,
+    multiplier NUMERIC(6, 2) NOT NULL CHECK (multiplier > 1),
+    applies_to_tasks BOOLEAN NOT NULL DEFAULT true,
+    applies_to_referrals BOOLEAN NOT NULL DEFAULT false,
+    budget BIGINT CHECK (budget > 0),
+    spent BIGINT NOT NULL DEFAULT 0,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_campaign_period CHECK (ends_at > starts_at),
+    CONSTRAINT chk_campaign_spent CHECK (spent >= 0 AND (budget IS NULL OR spent <= budget))
+);
+
+-- Create index for looking up running campaigns
+CREATE INDEX IF NOT EXISTS idx_campaigns_period ON campaigns(starts_at, ends_at) WHERE is_active = true;
+
+-- Task categories a campaign is limited to, a campaign without categories boosts all tasks
+CREATE TABLE IF NOT EXISTS campaign_categories (
+    campaign_id BIGINT NOT NULL,
+    category_id BIGINT NOT NULL,
+    PRIMARY KEY (campaign_id, category_id),
+    CONSTRAINT fk_campaign_category_campaign FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE,
+    CONSTRAINT fk_campaign_category_category FOREIGN KEY (category_id) REFERENCES task_categories(id) ON DELETE CASCADE
+);
+
+-- Campaign that boosted the transaction
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS campaign_id BIGINT REFERENCES campaigns(id) ON DELETE SET NULL;
+
+-- Create index for campaign reports
+CREATE INDEX IF NOT EXISTS idx_transactions_campaign_id ON transactions(campaign_id) WHERE campaign_id IS NOT NULL;
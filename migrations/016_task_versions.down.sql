@@ -0,0 +1,11 @@
+-- Drop task versioning triggers
+DROP TRIGGER IF EXISTS trigger_record_task_version ON tasks;
+DROP TRIGGER IF EXISTS trigger_bump_task_version ON tasks;
+DROP FUNCTION IF EXISTS record_task_version();
+DROP FUNCTION IF EXISTS bump_task_version();
+
+-- Drop awarded points and versions
+ALTER TABLE user_tasks DROP COLUMN IF EXISTS reward_points;
+ALTER TABLE user_tasks DROP COLUMN IF EXISTS task_version;
+DROP TABLE IF EXISTS task_versions;
+ALTER TABLE tasks DROP COLUMN IF EXISTS version;
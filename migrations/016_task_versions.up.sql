@@ -0,0 +1,67 @@
+-- Current version of task title and reward
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+-- History of task titles and rewards, one row per version
+CREATE TABLE IF NOT EXISTS task_versions (
+    task_id BIGINT NOT NULL,
+    version INT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    reward_points BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, version),
+    CONSTRAINT fk_task_version_task FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+-- Task version a completion was made against and default currency points actually awarded
+-- for it (NULL until the completion is rewarded)
+ALTER TABLE user_tasks ADD COLUMN IF NOT EXISTS task_version INT NOT NULL DEFAULT 1;
+ALTER TABLE user_tasks ADD COLUMN IF NOT EXISTS reward_points BIGINT;
+
+-- Function to start a new task version when its title or reward changes
+CREATE OR REPLACE FUNCTION bump_task_version()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.title IS DISTINCT FROM OLD.title OR NEW.reward_points IS DISTINCT FROM OLD.reward_points THEN
+        NEW.version := OLD.version + 1;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function to record task versions in history
+CREATE OR REPLACE FUNCTION record_task_version()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO task_versions (task_id, version, title, reward_points)
+    VALUES (NEW.id, NEW.version, NEW.title, NEW.reward_points)
+    ON CONFLICT (task_id, version) DO NOTHING;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_bump_task_version ON tasks;
+CREATE TRIGGER trigger_bump_task_version
+    BEFORE UPDATE OF title, reward_points ON tasks
+    FOR EACH ROW
+    EXECUTE FUNCTION bump_task_version();
+
+DROP TRIGGER IF EXISTS trigger_record_task_version ON tasks;
+CREATE TRIGGER trigger_record_task_version
+    AFTER INSERT OR UPDATE OF title, reward_points ON tasks
+    FOR EACH ROW
+    EXECUTE FUNCTION record_task_version();
+
+-- First version of existing tasks
+INSERT INTO task_versions (task_id, version, title, reward_points, created_at)
+SELECT id, version, title, reward_points, created_at
+FROM tasks
+ON CONFLICT (task_id, version) DO NOTHING;
+
+-- Points awarded for existing completions, taken from their reward transactions
+UPDATE user_tasks ut
+SET reward_points = COALESCE((
+    SELECT SUM(tr.delta) FROM transactions tr
+    WHERE tr.user_task_id = ut.id AND tr.currency = 'points' AND tr.reference_type = 'task'
+), t.reward_points)
+FROM tasks t
+WHERE t.id = ut.task_id AND ut.status = 'completed' AND ut.reward_points IS NULL;
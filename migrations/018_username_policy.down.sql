@@ -0,0 +1,3 @@
+-- Drop normalized usernames
+DROP INDEX IF EXISTS idx_users_username_normalized;
+ALTER TABLE users DROP COLUMN IF EXISTS username_normalized;
@@ -0,0 +1,26 @@
+-- Usernames are unique case-insensitively and up to look-alike characters
+ALTER TABLE users ADD COLUMN IF NOT EXISTS username_normalized VARCHAR(255);
+
+-- Backfill with the folding of entities.NormalizeUsername: NFKC, lower case and confusable characters
+UPDATE users
+SET username_normalized = translate(
+    lower(normalize(username, NFKC)),
+    'аеорсухіјѕԁһӏԛԝοανικρυχɡ01',
+    'aeopcyxijsdhlqwoavikpuxgol'
+)
+WHERE username_normalized IS NULL;
+
+-- Existing variants of the same username keep their usernames, all but the oldest get
+-- a key no valid username normalizes to
+UPDATE users u
+SET username_normalized = u.username_normalized || '#' || u.id
+FROM (
+    SELECT id, row_number() OVER (PARTITION BY username_normalized ORDER BY created_at, id) AS n
+    FROM users
+) d
+WHERE d.id = u.id AND d.n > 1;
+
+ALTER TABLE users ALTER COLUMN username_normalized SET NOT NULL;
+
+-- Create unique index for normalized usernames
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username_normalized ON users(username_normalized);
@@ -0,0 +1,8 @@
+-- Drop status audit
+DROP TABLE IF EXISTS user_status_changes;
+
+-- Drop account status
+DROP INDEX IF EXISTS idx_users_restricted;
+ALTER TABLE users DROP COLUMN IF EXISTS status_until;
+ALTER TABLE users DROP COLUMN IF EXISTS status_reason;
+ALTER TABLE users DROP COLUMN IF EXISTS status;
@@ -0,0 +1,25 @@
+-- Moderation state of users, a restriction with status_until ends on its own
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'suspended', 'banned'));
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status_reason TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status_until TIMESTAMPTZ;
+
+-- Create index for restricted users excluded from leaderboards
+CREATE INDEX IF NOT EXISTS idx_users_restricted ON users(id) WHERE status <> 'active';
+
+-- Audit of user status changes
+CREATE TABLE IF NOT EXISTS user_status_changes (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    actor_id BIGINT,
+    status VARCHAR(20) NOT NULL,
+    previous_status VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user_status_changes_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_status_changes_actor FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Create index for status history of a user
+CREATE INDEX IF NOT EXISTS idx_user_status_changes_user_id ON user_status_changes(user_id, created_at DESC);
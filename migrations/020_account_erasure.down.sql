@@ -0,0 +1,13 @@
+-- Drop deletion receipts
+DROP TABLE IF EXISTS deletion_receipts;
+
+-- Restore cascading deletes of balances and transactions
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS fk_transaction_user;
+ALTER TABLE transactions ADD CONSTRAINT fk_transaction_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE balances DROP CONSTRAINT IF EXISTS fk_balance_user;
+ALTER TABLE balances ADD CONSTRAINT fk_balance_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+-- Drop soft deletion
+DROP INDEX IF EXISTS idx_users_pending_erasure;
+ALTER TABLE users DROP COLUMN IF EXISTS anonymized_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,27 @@
+-- Soft deletion: deleted users are anonymised after a grace period, ledger rows are kept
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMPTZ;
+
+-- Create index for deleted users awaiting anonymisation
+CREATE INDEX IF NOT EXISTS idx_users_pending_erasure ON users(deleted_at) WHERE deleted_at IS NOT NULL AND anonymized_at IS NULL;
+
+-- Users are never deleted while they have balances or transactions
+ALTER TABLE balances DROP CONSTRAINT IF EXISTS fk_balance_user;
+ALTER TABLE balances ADD CONSTRAINT fk_balance_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS fk_transaction_user;
+ALTER TABLE transactions ADD CONSTRAINT fk_transaction_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT;
+
+-- Receipts of anonymised users
+CREATE TABLE IF NOT EXISTS deletion_receipts (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL UNIQUE,
+    requested_by BIGINT,
+    requested_at TIMESTAMPTZ NOT NULL,
+    erased_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    erased_fields TEXT[] NOT NULL,
+    username_changes_deleted BIGINT NOT NULL DEFAULT 0,
+    proofs_scrubbed BIGINT NOT NULL DEFAULT 0,
+    transactions_retained BIGINT NOT NULL DEFAULT 0,
+    CONSTRAINT fk_deletion_receipt_user FOREIGN KEY (user_id) REFERENCES users(id),
+    CONSTRAINT fk_deletion_receipt_requested_by FOREIGN KEY (requested_by) REFERENCES users(id) ON DELETE SET NULL
+);
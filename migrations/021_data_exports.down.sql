@@ -0,0 +1,2 @@
+-- Drop personal data exports
+DROP TABLE IF EXISTS data_exports;
@@ -0,0 +1,21 @@
+-- Personal data exports built in the background and downloadable until they expire
+CREATE TABLE IF NOT EXISTS data_exports (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    format VARCHAR(10) NOT NULL CHECK (format IN ('json', 'zip')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'processing', 'ready', 'failed', 'expired')),
+    content BYTEA,
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    started_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ,
+    CONSTRAINT fk_data_export_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Create index for exports of a user
+CREATE INDEX IF NOT EXISTS idx_data_exports_user_id ON data_exports(user_id, created_at DESC);
+
+-- Create index for the export queue
+CREATE INDEX IF NOT EXISTS idx_data_exports_queue ON data_exports(created_at) WHERE status IN ('pending', 'processing');
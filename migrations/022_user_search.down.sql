@@ -0,0 +1,3 @@
+-- Drop user search indexes, pg_trgm is left installed
+DROP INDEX IF EXISTS idx_users_created_at;
+DROP INDEX IF EXISTS idx_users_username_trgm;
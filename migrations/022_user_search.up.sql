@@ -0,0 +1,8 @@
+-- Trigram matching for username prefix and substring search
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Create index for username search
+CREATE INDEX IF NOT EXISTS idx_users_username_trgm ON users USING gin (username_normalized gin_trgm_ops);
+
+-- Create index for listing users by registration date
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users(created_at, id);